@@ -0,0 +1,191 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+var (
+	// ErrInvalidRate is returned when a target false-positive rate is not in (0,1).
+	ErrInvalidRate = errors.New("set: false-positive rate must be in (0,1)")
+	// ErrIncompatibleFilters is returned when combining filters built with different parameters.
+	ErrIncompatibleFilters = errors.New("set: filters have different parameters")
+	// ErrInvalidEncoding is returned when decoding malformed serialised data.
+	ErrInvalidEncoding = errors.New("set: invalid encoding")
+)
+
+const bloomEncodingVersion = 1
+
+// BloomFilter is a probabilistic set that answers membership queries with no false negatives and a
+// bounded rate of false positives, using far less memory than a Set of the same elements.
+//
+// A filter sized for n elements at a false-positive rate p uses m = -n·ln(p)/ln(2)² bits and
+// k = (m/n)·ln(2) hash functions.
+type BloomFilter struct {
+	m, k uint64
+	bits []uint64
+}
+
+// NewBloomFilter returns an empty filter sized to hold n elements at the false-positive rate p.
+func NewBloomFilter(n int, p float64) (*BloomFilter, error) {
+	if !(p > 0 && p < 1) {
+		return nil, ErrInvalidRate
+	}
+	if n < 1 {
+		n = 1
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	return newBloomFilter(uint64(m), uint64(k)), nil
+}
+
+func newBloomFilter(m, k uint64) *BloomFilter {
+	return &BloomFilter{m: m, k: k, bits: make([]uint64, (m+63)/64)}
+}
+
+// BloomFilterFromSet returns a filter holding every element of A at the false-positive rate p.
+func BloomFilterFromSet(A Interface, p float64) (*BloomFilter, error) {
+	f, err := NewBloomFilter(A.Len(), p)
+	if err != nil {
+		return nil, err
+	}
+	A.Iterate(func(e interface{}) bool {
+		f.Add(e)
+		return true
+	})
+	return f, nil
+}
+
+// Add inserts one or more elements into f.
+func (f *BloomFilter) Add(els ...interface{}) {
+	for _, e := range els {
+		h1, h2 := bloomHashes(e)
+		for i := uint64(0); i < f.k; i++ {
+			b := (h1 + i*h2) % f.m
+			f.bits[b/64] |= 1 << (b % 64)
+		}
+	}
+}
+
+// MayContain reports whether e may be in f. False means e was definitely never added.
+func (f *BloomFilter) MayContain(e interface{}) bool {
+	h1, h2 := bloomHashes(e)
+	for i := uint64(0); i < f.k; i++ {
+		b := (h1 + i*h2) % f.m
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes returns the two hashes combined by double hashing, gᵢ(x) = h₁(x) + i·h₂(x).
+func bloomHashes(e interface{}) (uint64, uint64) {
+	return hashElement(e, 0), hashElement(e, 1) | 1
+}
+
+// Bits returns the size of f in bits.
+func (f *BloomFilter) Bits() uint64 {
+	return f.m
+}
+
+// Hashes returns the number of hash functions used by f.
+func (f *BloomFilter) Hashes() uint64 {
+	return f.k
+}
+
+func (f *BloomFilter) ones() (n uint64) {
+	for _, w := range f.bits {
+		n += uint64(bits.OnesCount64(w))
+	}
+	return
+}
+
+// EstimatedCardinality returns an estimate of the number of distinct elements added to f,
+// computed from the fraction of bits that are set. A full filter gives +Inf.
+//
+// n* = -(m/k)·ln(1 - X/m)
+func (f *BloomFilter) EstimatedCardinality() float64 {
+	x := float64(f.ones())
+	m := float64(f.m)
+	return -m / float64(f.k) * math.Log(1-x/m)
+}
+
+// FalsePositiveRate returns the probability that MayContain reports an element that was never
+// added, given the bits currently set in f.
+//
+// p = (X/m)ᵏ
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	return math.Pow(float64(f.ones())/float64(f.m), float64(f.k))
+}
+
+func (f *BloomFilter) compatible(g *BloomFilter) bool {
+	return f.m == g.m && f.k == g.k
+}
+
+// Union returns a new filter for the union of the elements of f & g. The result is exactly the
+// filter that would have been built by adding the elements of both.
+// ErrIncompatibleFilters is returned if f & g have different sizes or hash counts.
+func (f *BloomFilter) Union(g *BloomFilter) (*BloomFilter, error) {
+	if !f.compatible(g) {
+		return nil, ErrIncompatibleFilters
+	}
+	u := newBloomFilter(f.m, f.k)
+	for i := range u.bits {
+		u.bits[i] = f.bits[i] | g.bits[i]
+	}
+	return u, nil
+}
+
+// Intersect returns a new filter for the intersection of the elements of f & g. It never misses
+// an element of the intersection, but its false-positive rate may be higher than that of a filter
+// built from the intersection directly.
+// ErrIncompatibleFilters is returned if f & g have different sizes or hash counts.
+func (f *BloomFilter) Intersect(g *BloomFilter) (*BloomFilter, error) {
+	if !f.compatible(g) {
+		return nil, ErrIncompatibleFilters
+	}
+	u := newBloomFilter(f.m, f.k)
+	for i := range u.bits {
+		u.bits[i] = f.bits[i] & g.bits[i]
+	}
+	return u, nil
+}
+
+// MarshalBinary encodes f as a version byte, the hash count and bit count as little-endian
+// uint64s, followed by the bit array as little-endian uint64 words.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 17+8*len(f.bits))
+	buf[0] = bloomEncodingVersion
+	binary.LittleEndian.PutUint64(buf[1:], f.k)
+	binary.LittleEndian.PutUint64(buf[9:], f.m)
+	for i, w := range f.bits {
+		binary.LittleEndian.PutUint64(buf[17+8*i:], w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a filter encoded by MarshalBinary into f.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 17 || data[0] != bloomEncodingVersion {
+		return ErrInvalidEncoding
+	}
+	k := binary.LittleEndian.Uint64(data[1:])
+	m := binary.LittleEndian.Uint64(data[9:])
+	// The word count is computed without overflow, so that no m can claim fewer words than it needs.
+	words := m / 64
+	if m%64 != 0 {
+		words++
+	}
+	if k == 0 || m == 0 || (len(data)-17)%8 != 0 || uint64(len(data)-17)/8 != words {
+		return ErrInvalidEncoding
+	}
+	g := newBloomFilter(m, k)
+	for i := range g.bits {
+		g.bits[i] = binary.LittleEndian.Uint64(data[17+8*i:])
+	}
+	*f = *g
+	return nil
+}
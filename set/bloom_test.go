@@ -0,0 +1,129 @@
+package set
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func Test_NewBloomFilter(t *testing.T) {
+	if _, err := NewBloomFilter(100, 0); err != ErrInvalidRate {
+		t.Errorf("Expecting ErrInvalidRate instead got %v", err)
+	}
+	if _, err := NewBloomFilter(100, 1); err != ErrInvalidRate {
+		t.Errorf("Expecting ErrInvalidRate instead got %v", err)
+	}
+	f, err := NewBloomFilter(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Bits() != 9586 || f.Hashes() != 7 {
+		t.Errorf("Expecting 9586 bits & 7 hashes instead got %d & %d", f.Bits(), f.Hashes())
+	}
+}
+
+func Test_BloomFilterFalsePositiveRate(t *testing.T) {
+	for _, p := range []float64{0.1, 0.01, 0.001} {
+		A := NewSet()
+		for i := 0; i < 10000; i++ {
+			A.Add(i)
+		}
+		f, err := BloomFilterFromSet(A, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for e := range A.E {
+			if !f.MayContain(e) {
+				t.Fatalf("Expecting no false negatives, %v is missing", e)
+			}
+		}
+		fp := 0
+		trials := 200000
+		for i := 0; i < trials; i++ {
+			if f.MayContain(-1 - i) {
+				fp++
+			}
+		}
+		rate := float64(fp) / float64(trials)
+		if rate > 1.25*p {
+			t.Errorf("Expecting a false-positive rate of about %v instead measured %v", p, rate)
+		}
+		if est := f.FalsePositiveRate(); math.Abs(est-p)/p > 0.25 {
+			t.Errorf("Expecting an estimated false-positive rate of about %v instead got %v", p, est)
+		}
+	}
+}
+
+func Test_BloomFilterSetAlgebra(t *testing.T) {
+	A, B := NewSet(), NewSet()
+	for i := 0; i < 3000; i++ {
+		A.Add(i)
+		B.Add(i + 2000)
+	}
+	fa, _ := NewBloomFilter(5000, 0.01)
+	fb, _ := NewBloomFilter(5000, 0.01)
+	fa.Add(A.SetToSlice()...)
+	fb.Add(B.SetToSlice()...)
+	u, err := fa.Union(fb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for e := range Union(A, B).E {
+		if !u.MayContain(e) {
+			t.Fatalf("Expecting the union filter to contain %v", e)
+		}
+	}
+	i, err := fa.Intersect(fb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for e := range Intersect(A, B).E {
+		if !i.MayContain(e) {
+			t.Fatalf("Expecting the intersection filter to contain %v", e)
+		}
+	}
+	if est := u.EstimatedCardinality(); math.Abs(est-5000)/5000 > 0.05 {
+		t.Errorf("Expecting an estimated union cardinality of about 5000 instead got %f", est)
+	}
+	if est := fa.EstimatedCardinality(); math.Abs(est-3000)/3000 > 0.05 {
+		t.Errorf("Expecting an estimated cardinality of about 3000 instead got %f", est)
+	}
+	small, _ := NewBloomFilter(10, 0.01)
+	if _, err := fa.Union(small); err != ErrIncompatibleFilters {
+		t.Errorf("Expecting ErrIncompatibleFilters instead got %v", err)
+	}
+	if _, err := fa.Intersect(small); err != ErrIncompatibleFilters {
+		t.Errorf("Expecting ErrIncompatibleFilters instead got %v", err)
+	}
+}
+
+func Test_BloomFilterSerialization(t *testing.T) {
+	f, _ := BloomFilterFromSet(NewSet("cat", "dog", 1, 2.5, true, NewTuple(1, "a")), 0.01)
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g BloomFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []interface{}{"cat", "dog", 1, 2.5, true, NewTuple(1, "a")} {
+		if !g.MayContain(e) {
+			t.Errorf("Expecting the decoded filter to contain %v", e)
+		}
+	}
+	if u, err := f.Union(&g); err != nil || u.ones() != f.ones() {
+		t.Errorf("Expecting the decoded filter to be identical to the original, got %v", err)
+	}
+	if err := g.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidEncoding {
+		t.Errorf("Expecting ErrInvalidEncoding for truncated data instead got %v", err)
+	}
+	// A bit count near 2⁶⁴ must not wrap round to a word count matching the data.
+	header := append([]byte(nil), data[:17]...)
+	for _, m := range []uint64{math.MaxUint64, math.MaxUint64 - 62} {
+		binary.LittleEndian.PutUint64(header[9:], m)
+		if err := g.UnmarshalBinary(header); err != ErrInvalidEncoding {
+			t.Errorf("Expecting ErrInvalidEncoding for %d bits instead got %v", m, err)
+		}
+	}
+}
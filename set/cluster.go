@@ -0,0 +1,215 @@
+package set
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidClusterCount is returned when asking for fewer than 1 cluster, or more clusters than sets.
+var ErrInvalidClusterCount = errors.New("set: number of clusters must be between 1 and the number of sets")
+
+// ErrInvalidDistance is returned when a distance matrix holds a NaN, which cannot be ordered.
+var ErrInvalidDistance = errors.New("set: distances must not be NaN")
+
+// Linkage is the distance between two clusters used by hierarchical clustering.
+type Linkage int
+
+const (
+	// SingleLinkage is the smallest distance between members of the two clusters.
+	SingleLinkage Linkage = iota
+	// CompleteLinkage is the largest distance between members of the two clusters.
+	CompleteLinkage
+	// AverageLinkage is the mean distance between members of the two clusters (UPGMA).
+	AverageLinkage
+)
+
+// clusters returns the names in each group of indices as a set of sets.
+func (M *Matrix) clusters(groups [][]int) *Set {
+	C := NewSet()
+	for _, g := range groups {
+		S := NewSet()
+		for _, i := range g {
+			S.Add(M.names[i])
+		}
+		C.Add(S)
+	}
+	return C
+}
+
+// hasNaN checks if any value of M is NaN.
+func (M *Matrix) hasNaN() bool {
+	for _, row := range M.values {
+		for _, v := range row {
+			if math.IsNaN(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Hierarchical groups the sets of the distance matrix D, such as one from
+// PairwiseDistance(sets, Jaccard), into k clusters by agglomerative clustering: starting from
+// one cluster per set, the two closest clusters under linkage are merged until k remain. Ties
+// are broken in the order of D.Names, so the result is deterministic.
+// The clusters are returned as a set of sets of names.
+// ErrInvalidClusterCount is returned unless 1 ≤ k ≤ D.Len(), and ErrInvalidDistance if D holds a
+// NaN.
+func (D *Matrix) Hierarchical(k int, linkage Linkage) (*Set, error) {
+	n := D.Len()
+	if k < 1 || k > n {
+		return nil, ErrInvalidClusterCount
+	}
+	if D.hasNaN() {
+		return nil, ErrInvalidDistance
+	}
+	d := make([][]float64, n)
+	groups := make([][]int, n)
+	for i := range d {
+		d[i] = append([]float64(nil), D.values[i]...)
+		groups[i] = []int{i}
+	}
+	active := make([]bool, n)
+	for i := range active {
+		active[i] = true
+	}
+	for remaining := n; remaining > k; remaining-- {
+		bi, bj, best := -1, -1, math.Inf(1)
+		for i := 0; i < n; i++ {
+			if !active[i] {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if active[j] && (bi < 0 || d[i][j] < best) {
+					bi, bj, best = i, j, d[i][j]
+				}
+			}
+		}
+		// The Lance–Williams update gives the distance from the merged cluster to every other.
+		ni, nj := float64(len(groups[bi])), float64(len(groups[bj]))
+		for l := 0; l < n; l++ {
+			if !active[l] || l == bi || l == bj {
+				continue
+			}
+			var v float64
+			switch linkage {
+			case SingleLinkage:
+				v = math.Min(d[bi][l], d[bj][l])
+			case CompleteLinkage:
+				v = math.Max(d[bi][l], d[bj][l])
+			case AverageLinkage:
+				v = (ni*d[bi][l] + nj*d[bj][l]) / (ni + nj)
+			}
+			d[bi][l], d[l][bi] = v, v
+		}
+		groups[bi] = append(groups[bi], groups[bj]...)
+		active[bj] = false
+	}
+	var result [][]int
+	for i, g := range groups {
+		if active[i] {
+			result = append(result, g)
+		}
+	}
+	return D.clusters(result), nil
+}
+
+// KMedoids groups the sets of the distance matrix D, such as one from
+// PairwiseDistance(sets, Jaccard), into k clusters around k of the sets, the medoids, chosen to
+// minimise the total distance from each set to its nearest medoid. It uses the PAM algorithm:
+// medoids are chosen greedily, then swapped with other sets while that lowers the total.
+// The clusters are returned as a set of sets of names, along with the names of the medoids.
+// ErrInvalidClusterCount is returned unless 1 ≤ k ≤ D.Len(), and ErrInvalidDistance if D holds a
+// NaN.
+func (D *Matrix) KMedoids(k int) (*Set, []string, error) {
+	n := D.Len()
+	if k < 1 || k > n {
+		return nil, nil, ErrInvalidClusterCount
+	}
+	if D.hasNaN() {
+		return nil, nil, ErrInvalidDistance
+	}
+	d := D.values
+	isMedoid := make([]bool, n)
+	nearest := make([]float64, n)
+	for i := range nearest {
+		nearest[i] = math.Inf(1)
+	}
+	var medoids []int
+	for len(medoids) < k {
+		best, bestGain := -1, math.Inf(-1)
+		for c := 0; c < n; c++ {
+			if isMedoid[c] {
+				continue
+			}
+			gain := 0.0
+			for j := 0; j < n; j++ {
+				if len(medoids) == 0 {
+					gain -= d[c][j]
+				} else if d[c][j] < nearest[j] {
+					gain += nearest[j] - d[c][j]
+				}
+			}
+			if gain > bestGain {
+				best, bestGain = c, gain
+			}
+		}
+		medoids = append(medoids, best)
+		isMedoid[best] = true
+		for j := range nearest {
+			nearest[j] = math.Min(nearest[j], d[best][j])
+		}
+	}
+	cost := func() (total float64) {
+		for j := 0; j < n; j++ {
+			min := math.Inf(1)
+			for _, m := range medoids {
+				min = math.Min(min, d[m][j])
+			}
+			total += min
+		}
+		return
+	}
+	for current := cost(); ; {
+		bestCost, bestM, bestH := current, -1, -1
+		for mi, m := range medoids {
+			for h := 0; h < n; h++ {
+				if isMedoid[h] {
+					continue
+				}
+				medoids[mi] = h
+				if c := cost(); c < bestCost {
+					bestCost, bestM, bestH = c, mi, h
+				}
+				medoids[mi] = m
+			}
+		}
+		if bestM < 0 {
+			break
+		}
+		isMedoid[medoids[bestM]], isMedoid[bestH] = false, true
+		medoids[bestM] = bestH
+		current = bestCost
+	}
+	groups := make([][]int, k)
+	for mi, m := range medoids {
+		groups[mi] = []int{m}
+	}
+	for j := 0; j < n; j++ {
+		if isMedoid[j] {
+			continue
+		}
+		nearest := 0
+		for mi, m := range medoids {
+			if d[m][j] < d[medoids[nearest]][j] {
+				nearest = mi
+			}
+		}
+		groups[nearest] = append(groups[nearest], j)
+	}
+	names := make([]string, k)
+	for mi, m := range medoids {
+		names[mi] = D.names[m]
+	}
+	return D.clusters(groups), names, nil
+}
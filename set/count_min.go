@@ -0,0 +1,199 @@
+package set
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidErrorBound is returned when a sketch error bound or failure probability is not in (0,1).
+var ErrInvalidErrorBound = errors.New("set: epsilon and delta must be in (0,1)")
+
+// CountMinSketch estimates how many times each element was added to a multiset, in memory that
+// depends only on the accuracy required. A sketch with ε and δ has ⌈e/ε⌉ counters in each of
+// ⌈ln(1/δ)⌉ rows. Estimates are never below the true count, and with probability 1-δ they are at
+// most εN above it, where N is the total of all counts added.
+type CountMinSketch struct {
+	width, depth uint64
+	counts       [][]uint64
+	conservative bool
+	total        uint64
+}
+
+// NewCountMinSketch returns an empty sketch whose estimates are within εN of the true count with
+// probability 1-δ.
+func NewCountMinSketch(epsilon, delta float64) (*CountMinSketch, error) {
+	if !(epsilon > 0 && epsilon < 1 && delta > 0 && delta < 1) {
+		return nil, ErrInvalidErrorBound
+	}
+	s := &CountMinSketch{
+		width: uint64(math.Ceil(math.E / epsilon)),
+		depth: uint64(math.Ceil(math.Log(1 / delta))),
+	}
+	s.counts = make([][]uint64, s.depth)
+	for i := range s.counts {
+		s.counts[i] = make([]uint64, s.width)
+	}
+	return s, nil
+}
+
+// NewConservativeCountMinSketch returns an empty sketch like NewCountMinSketch that uses
+// conservative update: an addition only raises the counters of an element up to its new
+// estimate, rather than adding to all of them. This gives the same guarantees with smaller
+// overestimates, but elements can then only be added, never subtracted.
+func NewConservativeCountMinSketch(epsilon, delta float64) (*CountMinSketch, error) {
+	s, err := NewCountMinSketch(epsilon, delta)
+	if err != nil {
+		return nil, err
+	}
+	s.conservative = true
+	return s, nil
+}
+
+func (s *CountMinSketch) cell(row uint64, h1, h2 uint64) uint64 {
+	return (h1 + row*h2) % s.width
+}
+
+// Add records count more occurrences of e.
+func (s *CountMinSketch) Add(e interface{}, count uint64) {
+	h1, h2 := bloomHashes(e)
+	s.total += count
+	if !s.conservative {
+		for row := uint64(0); row < s.depth; row++ {
+			s.counts[row][s.cell(row, h1, h2)] += count
+		}
+		return
+	}
+	target := s.estimate(h1, h2) + count
+	for row := uint64(0); row < s.depth; row++ {
+		if c := &s.counts[row][s.cell(row, h1, h2)]; *c < target {
+			*c = target
+		}
+	}
+}
+
+// Count returns the estimated number of occurrences of e.
+//
+// ĉ(x) = minᵢ count[i][hᵢ(x)]
+func (s *CountMinSketch) Count(e interface{}) uint64 {
+	h1, h2 := bloomHashes(e)
+	return s.estimate(h1, h2)
+}
+
+func (s *CountMinSketch) estimate(h1, h2 uint64) uint64 {
+	min := uint64(math.MaxUint64)
+	for row := uint64(0); row < s.depth; row++ {
+		if c := s.counts[row][s.cell(row, h1, h2)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// Total returns the sum of all counts added to s.
+func (s *CountMinSketch) Total() uint64 {
+	return s.total
+}
+
+// ErrorBound returns the amount by which an estimate exceeds the true count with probability
+// at most δ.
+//
+// εN = (e/w)·N
+func (s *CountMinSketch) ErrorBound() float64 {
+	return math.E / float64(s.width) * float64(s.total)
+}
+
+// Merge adds every count recorded in t to s, so that s estimates counts across both.
+// ErrIncompatibleSketches is returned if s & t have different dimensions.
+func (s *CountMinSketch) Merge(t *CountMinSketch) error {
+	if s.width != t.width || s.depth != t.depth {
+		return ErrIncompatibleSketches
+	}
+	for row := range s.counts {
+		for i, c := range t.counts[row] {
+			s.counts[row][i] += c
+		}
+	}
+	s.total += t.total
+	return nil
+}
+
+// HeavyHitters tracks the k elements with the highest estimated counts in a stream, using a
+// conservative CountMinSketch for the counts.
+type HeavyHitters struct {
+	k      int
+	sketch *CountMinSketch
+	top    map[interface{}]uint64
+}
+
+// NewHeavyHitters returns an empty tracker of the top k elements, with counts estimated within
+// εN with probability 1-δ.
+func NewHeavyHitters(k int, epsilon, delta float64) (*HeavyHitters, error) {
+	if k < 1 {
+		return nil, ErrInvalidSize
+	}
+	s, err := NewConservativeCountMinSketch(epsilon, delta)
+	if err != nil {
+		return nil, err
+	}
+	return &HeavyHitters{k: k, sketch: s, top: make(map[interface{}]uint64, k+1)}, nil
+}
+
+// Add records count more occurrences of e.
+func (h *HeavyHitters) Add(e interface{}, count uint64) {
+	h.sketch.Add(e, count)
+	h.offer(e, h.sketch.Count(e))
+}
+
+// offer makes e a top element if its estimate is higher than that of the lowest one.
+func (h *HeavyHitters) offer(e interface{}, estimate uint64) {
+	if _, ok := h.top[e]; ok || len(h.top) < h.k {
+		h.top[e] = estimate
+		return
+	}
+	var lowest interface{}
+	min := uint64(math.MaxUint64)
+	for t, c := range h.top {
+		if c < min {
+			lowest, min = t, c
+		}
+	}
+	if estimate > min {
+		delete(h.top, lowest)
+		h.top[e] = estimate
+	}
+}
+
+// Count returns the estimated number of occurrences of e.
+func (h *HeavyHitters) Count(e interface{}) uint64 {
+	return h.sketch.Count(e)
+}
+
+// TopK returns the set of the k elements with the highest estimated counts.
+func (h *HeavyHitters) TopK() *Set {
+	T := NewSet()
+	for e := range h.top {
+		T.Add(e)
+	}
+	return T
+}
+
+// Merge adds every count recorded in g to h. The top elements of both are re-estimated against
+// the merged counts and the highest k kept, so h then tracks the heavy hitters of both streams.
+// ErrIncompatibleSketches is returned if h & g were created with different ε or δ.
+func (h *HeavyHitters) Merge(g *HeavyHitters) error {
+	if err := h.sketch.Merge(g.sketch); err != nil {
+		return err
+	}
+	candidates := make([]interface{}, 0, len(h.top)+len(g.top))
+	for e := range h.top {
+		candidates = append(candidates, e)
+	}
+	for e := range g.top {
+		candidates = append(candidates, e)
+	}
+	h.top = make(map[interface{}]uint64, h.k+1)
+	for _, e := range candidates {
+		h.offer(e, h.sketch.Count(e))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// zipfStream returns n draws from a Zipf distribution over 10000 elements and their exact counts.
+func zipfStream(n int, seed int64) ([]uint64, map[uint64]uint64) {
+	z := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.2, 1, 9999)
+	stream := make([]uint64, n)
+	exact := make(map[uint64]uint64)
+	for i := range stream {
+		stream[i] = z.Uint64()
+		exact[stream[i]]++
+	}
+	return stream, exact
+}
+
+func Test_NewCountMinSketch(t *testing.T) {
+	if _, err := NewCountMinSketch(0, 0.01); err != ErrInvalidErrorBound {
+		t.Errorf("Expecting ErrInvalidErrorBound instead got %v", err)
+	}
+	s, _ := NewCountMinSketch(0.01, 0.01)
+	if s.width != 272 || s.depth != 5 {
+		t.Errorf("Expecting 5 rows of 272 counters instead got %d of %d", s.depth, s.width)
+	}
+}
+
+func Test_CountMinSketchErrorBound(t *testing.T) {
+	stream, exact := zipfStream(100000, 1)
+	standard, _ := NewCountMinSketch(0.001, 0.01)
+	conservative, _ := NewConservativeCountMinSketch(0.001, 0.01)
+	for _, e := range stream {
+		standard.Add(e, 1)
+		conservative.Add(e, 1)
+	}
+	bound := uint64(standard.ErrorBound())
+	if standard.Total() != 100000 || bound != 99 {
+		t.Errorf("Expecting a total of 100000 and a bound of 99 instead got %d & %d", standard.Total(), bound)
+	}
+	violations := 0
+	for e := uint64(0); e < 10000; e++ {
+		s, c := standard.Count(e), conservative.Count(e)
+		if s < exact[e] || c < exact[e] {
+			t.Fatalf("Expecting estimates never to undercount %d: true %d, got %d & %d", e, exact[e], s, c)
+		}
+		if c > s {
+			t.Fatalf("Expecting conservative update never to exceed the standard estimate for %d", e)
+		}
+		if s-exact[e] > bound {
+			violations++
+		}
+	}
+	if violations > 100 {
+		t.Errorf("Expecting at most 1%% of estimates beyond the error bound instead got %d", violations)
+	}
+}
+
+func Test_CountMinSketchMerge(t *testing.T) {
+	stream, exact := zipfStream(20000, 2)
+	a, _ := NewCountMinSketch(0.01, 0.01)
+	b, _ := NewCountMinSketch(0.01, 0.01)
+	for i, e := range stream {
+		if i%2 == 0 {
+			a.Add(e, 1)
+		} else {
+			b.Add(e, 1)
+		}
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Total() != 20000 || a.Count(uint64(1)) < exact[1] {
+		t.Errorf("Expecting the merged sketch to count both shards, got %d total", a.Total())
+	}
+	c, _ := NewCountMinSketch(0.1, 0.01)
+	if err := a.Merge(c); err != ErrIncompatibleSketches {
+		t.Errorf("Expecting ErrIncompatibleSketches instead got %v", err)
+	}
+}
+
+func Test_HeavyHitters(t *testing.T) {
+	if _, err := NewHeavyHitters(0, 0.01, 0.01); err != ErrInvalidSize {
+		t.Errorf("Expecting ErrInvalidSize instead got %v", err)
+	}
+	stream, _ := zipfStream(100000, 3)
+	whole, _ := NewHeavyHitters(5, 0.001, 0.01)
+	shards := []*HeavyHitters{}
+	for i := 0; i < 4; i++ {
+		h, _ := NewHeavyHitters(5, 0.001, 0.01)
+		shards = append(shards, h)
+	}
+	for i, e := range stream {
+		whole.Add(e, 1)
+		shards[i%4].Add(e, 1)
+	}
+	// With a Zipf exponent of 1.2 the five most frequent elements are 0 to 4.
+	want := NewSet(uint64(0), uint64(1), uint64(2), uint64(3), uint64(4))
+	if top := whole.TopK(); !top.IsEqual(want) {
+		t.Errorf("Expecting the top 5 to be %v instead got %v", want, top)
+	}
+	for _, h := range shards[1:] {
+		if err := shards[0].Merge(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if top := shards[0].TopK(); !top.IsEqual(want) {
+		t.Errorf("Expecting the merged top 5 to be %v instead got %v", want, top)
+	}
+	if shards[0].Count(uint64(0)) < whole.Count(uint64(0))*9/10 {
+		t.Errorf("Expecting merged counts close to the whole stream, got %d & %d", shards[0].Count(uint64(0)), whole.Count(uint64(0)))
+	}
+}
@@ -0,0 +1,185 @@
+package set
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrFilterFull is returned when an element cannot be inserted because the filter is too full.
+var ErrFilterFull = errors.New("set: filter is full")
+
+// Filter is a probabilistic set. MayContain never reports false for an element that was added,
+// but may report true for elements that were not, at a rate of about FalsePositiveRate.
+type Filter interface {
+	// MayContain reports whether e may be in the filter.
+	MayContain(e interface{}) bool
+	// FalsePositiveRate returns the expected rate of false positives of the filter as it is now.
+	FalsePositiveRate() float64
+	// Bits returns the size of the filter in bits.
+	Bits() uint64
+}
+
+const (
+	cuckooBucketSize = 4
+	cuckooMaxKicks   = 500
+	// cuckooMaxLoad is the load factor a cuckoo filter is sized for. Beyond about 95% with
+	// buckets of 4, insertions start to fail.
+	cuckooMaxLoad = 0.95
+)
+
+// CuckooFilter is a probabilistic set that, unlike BloomFilter, supports removing elements.
+// Each element is stored as a 16-bit fingerprint in one of two buckets of 4 slots, so the
+// false-positive rate is at most 8/2¹⁶ ≈ 0.012%.
+//
+// Every element should be added at most once, and only elements that were added should be
+// removed, otherwise the fingerprint of a different element may be deleted.
+type CuckooFilter struct {
+	buckets [][cuckooBucketSize]uint16
+	mask    uint64
+	count   int
+	rng     uint64
+}
+
+// NewCuckooFilter returns an empty filter with room for at least n elements.
+func NewCuckooFilter(n int) *CuckooFilter {
+	buckets := uint64(1)
+	for float64(buckets*cuckooBucketSize)*cuckooMaxLoad < float64(n) {
+		buckets <<= 1
+	}
+	return &CuckooFilter{
+		buckets: make([][cuckooBucketSize]uint16, buckets),
+		mask:    buckets - 1,
+		rng:     0x9e3779b97f4a7c15,
+	}
+}
+
+// CuckooFilterFromSet returns a filter holding every element of A.
+func CuckooFilterFromSet(A Interface) (f *CuckooFilter, err error) {
+	f = NewCuckooFilter(A.Len())
+	A.Iterate(func(e interface{}) bool {
+		err = f.Add(e)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// cuckooIndexes returns the fingerprint of e and its two candidate buckets.
+func (f *CuckooFilter) cuckooIndexes(e interface{}) (fp uint16, i1, i2 uint64) {
+	h := hashElement(e, 0)
+	fp = uint16(h >> 48)
+	if fp == 0 {
+		fp = 1
+	}
+	i1 = h & f.mask
+	return fp, i1, f.altIndex(i1, fp)
+}
+
+// altIndex returns the other bucket of a fingerprint stored in bucket i. It is its own inverse.
+func (f *CuckooFilter) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ mix64(uint64(fp))) & f.mask
+}
+
+func (f *CuckooFilter) insert(i uint64, fp uint16) bool {
+	for s, v := range f.buckets[i] {
+		if v == 0 {
+			f.buckets[i][s] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// Add inserts e into f. ErrFilterFull is returned, and f is left unchanged, if no slot could be
+// freed for e by relocating other fingerprints.
+func (f *CuckooFilter) Add(e interface{}) error {
+	fp, i1, i2 := f.cuckooIndexes(e)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.count++
+		return nil
+	}
+	type kick struct {
+		bucket uint64
+		slot   int
+		fp     uint16
+	}
+	path := make([]kick, 0, cuckooMaxKicks)
+	i := i1
+	if f.next()&1 == 1 {
+		i = i2
+	}
+	for n := 0; n < cuckooMaxKicks; n++ {
+		s := int(f.next() % cuckooBucketSize)
+		path = append(path, kick{i, s, f.buckets[i][s]})
+		fp, f.buckets[i][s] = f.buckets[i][s], fp
+		i = f.altIndex(i, fp)
+		if f.insert(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	for n := len(path) - 1; n >= 0; n-- {
+		k := path[n]
+		f.buckets[k.bucket][k.slot] = k.fp
+	}
+	return ErrFilterFull
+}
+
+// next returns the next value of the xorshift generator used to pick fingerprints to relocate.
+func (f *CuckooFilter) next() uint64 {
+	f.rng ^= f.rng << 13
+	f.rng ^= f.rng >> 7
+	f.rng ^= f.rng << 17
+	return f.rng
+}
+
+// MayContain reports whether e may be in f. False means e is definitely not in f.
+func (f *CuckooFilter) MayContain(e interface{}) bool {
+	fp, i1, i2 := f.cuckooIndexes(e)
+	for s := 0; s < cuckooBucketSize; s++ {
+		if f.buckets[i1][s] == fp || f.buckets[i2][s] == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove deletes e from f, returning false if e was not found.
+func (f *CuckooFilter) Remove(e interface{}) bool {
+	fp, i1, i2 := f.cuckooIndexes(e)
+	for _, i := range []uint64{i1, i2} {
+		for s := 0; s < cuckooBucketSize; s++ {
+			if f.buckets[i][s] == fp {
+				f.buckets[i][s] = 0
+				f.count--
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Len returns the number of elements in f.
+func (f *CuckooFilter) Len() int {
+	return f.count
+}
+
+// LoadFactor returns the fraction of slots in f that are occupied.
+func (f *CuckooFilter) LoadFactor() float64 {
+	return float64(f.count) / float64(len(f.buckets)*cuckooBucketSize)
+}
+
+// FalsePositiveRate returns the probability that MayContain reports an element that was never
+// added, given the load of f. A query compares against the 2b slots of its two buckets.
+//
+// p = 1 - (1 - 2⁻¹⁶)^(2b·α)
+func (f *CuckooFilter) FalsePositiveRate() float64 {
+	return 1 - math.Pow(1-1.0/65535, 2*cuckooBucketSize*f.LoadFactor())
+}
+
+// Bits returns the size of f in bits.
+func (f *CuckooFilter) Bits() uint64 {
+	return uint64(len(f.buckets)) * cuckooBucketSize * 16
+}
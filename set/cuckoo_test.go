@@ -0,0 +1,82 @@
+package set
+
+import "testing"
+
+func Test_FilterInterface(t *testing.T) {
+	var _ Filter = &BloomFilter{}
+	var _ Filter = &CuckooFilter{}
+	var _ Filter = &XorFilter{}
+}
+
+func Test_CuckooFilter(t *testing.T) {
+	A := NewSet()
+	for i := 0; i < 10000; i++ {
+		A.Add(i)
+	}
+	f, err := CuckooFilterFromSet(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Len() != 10000 {
+		t.Errorf("Expecting 10000 elements instead got %d", f.Len())
+	}
+	for e := range A.E {
+		if !f.MayContain(e) {
+			t.Fatalf("Expecting no false negatives, %v is missing", e)
+		}
+	}
+	fp := 0
+	trials := 200000
+	for i := 0; i < trials; i++ {
+		if f.MayContain(-1 - i) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / float64(trials); rate > 2*f.FalsePositiveRate() {
+		t.Errorf("Expecting a false-positive rate of about %v instead measured %v", f.FalsePositiveRate(), rate)
+	}
+	for i := 0; i < 5000; i++ {
+		if !f.Remove(i) {
+			t.Fatalf("Expecting %d to be removed", i)
+		}
+	}
+	if f.Len() != 5000 {
+		t.Errorf("Expecting 5000 elements after removal instead got %d", f.Len())
+	}
+	present := 0
+	for i := 0; i < 5000; i++ {
+		if f.MayContain(i) {
+			present++
+		}
+	}
+	if present > 10 {
+		t.Errorf("Expecting removed elements to be gone, %d still reported", present)
+	}
+	for i := 5000; i < 10000; i++ {
+		if !f.MayContain(i) {
+			t.Fatalf("Expecting %d to survive the removal of other elements", i)
+		}
+	}
+}
+
+func Test_CuckooFilterFull(t *testing.T) {
+	f := NewCuckooFilter(8)
+	var err error
+	added := 0
+	for i := 0; err == nil; i++ {
+		if err = f.Add(i); err == nil {
+			added++
+		}
+	}
+	if err != ErrFilterFull {
+		t.Errorf("Expecting ErrFilterFull instead got %v", err)
+	}
+	if f.Len() != added || f.LoadFactor() < 0.5 {
+		t.Errorf("Expecting a failed insert to leave the filter unchanged, got %d of %d at load %f", f.Len(), added, f.LoadFactor())
+	}
+	for i := 0; i < added; i++ {
+		if !f.MayContain(i) {
+			t.Errorf("Expecting %d to survive a failed insert", i)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package set
+
+// DisjointSet is a union-find structure that tracks a partition of its elements into disjoint blocks.
+// Find uses path compression and Union uses union by rank, so any sequence of operations runs in
+// near constant amortised time per operation.
+type DisjointSet struct {
+	parent map[interface{}]interface{}
+	rank   map[interface{}]int
+	blocks int
+}
+
+// NewDisjointSet returns a new disjoint set where every element passed in is in a block of its own.
+func NewDisjointSet(els ...interface{}) *DisjointSet {
+	D := &DisjointSet{
+		parent: make(map[interface{}]interface{}, len(els)),
+		rank:   make(map[interface{}]int, len(els)),
+	}
+	D.Add(els...)
+	return D
+}
+
+// DisjointSetFromPartition returns a new disjoint set with one block for each of the blocks passed in.
+// Blocks that share elements are merged.
+func DisjointSetFromPartition(blocks ...*Set) *DisjointSet {
+	D := NewDisjointSet()
+	for _, b := range blocks {
+		var first interface{}
+		started := false
+		for e := range b.E {
+			if !started {
+				first, started = e, true
+				D.Add(e)
+				continue
+			}
+			D.Union(first, e)
+		}
+	}
+	return D
+}
+
+// Add inserts one or more elements into D, each in a block of its own. Existing elements are left untouched.
+func (D *DisjointSet) Add(els ...interface{}) {
+	for _, e := range els {
+		if _, ok := D.parent[e]; !ok {
+			D.parent[e] = e
+			D.rank[e] = 0
+			D.blocks++
+		}
+	}
+}
+
+// Contains checks if one or more elements are in D.
+func (D *DisjointSet) Contains(els ...interface{}) bool {
+	for _, e := range els {
+		if _, ok := D.parent[e]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of elements in D.
+func (D *DisjointSet) Len() int {
+	return len(D.parent)
+}
+
+// Blocks returns the number of disjoint blocks in D.
+func (D *DisjointSet) Blocks() int {
+	return D.blocks
+}
+
+// Find returns the representative of the block containing x, and false if x is not in D.
+func (D *DisjointSet) Find(x interface{}) (interface{}, bool) {
+	if _, ok := D.parent[x]; !ok {
+		return nil, false
+	}
+	root := x
+	for D.parent[root] != root {
+		root = D.parent[root]
+	}
+	for x != root {
+		next := D.parent[x]
+		D.parent[x] = root
+		x = next
+	}
+	return root, true
+}
+
+// Union merges the blocks containing x and y. Elements not already in D are added first.
+func (D *DisjointSet) Union(x, y interface{}) {
+	D.Add(x, y)
+	rx, _ := D.Find(x)
+	ry, _ := D.Find(y)
+	if rx == ry {
+		return
+	}
+	switch {
+	case D.rank[rx] < D.rank[ry]:
+		D.parent[rx] = ry
+	case D.rank[rx] > D.rank[ry]:
+		D.parent[ry] = rx
+	default:
+		D.parent[ry] = rx
+		D.rank[rx]++
+	}
+	D.blocks--
+}
+
+// Connected checks if x and y are in the same block.
+func (D *DisjointSet) Connected(x, y interface{}) bool {
+	rx, ok := D.Find(x)
+	if !ok {
+		return false
+	}
+	ry, ok := D.Find(y)
+	return ok && rx == ry
+}
+
+// Block returns a new set of all elements in the same block as x, or an empty set if x is not in D.
+func (D *DisjointSet) Block(x interface{}) (B *Set) {
+	B = NewSet()
+	root, ok := D.Find(x)
+	if !ok {
+		return
+	}
+	for e := range D.parent {
+		if r, _ := D.Find(e); r == root {
+			B.Add(e)
+		}
+	}
+	return
+}
+
+// Components returns the blocks of D as a set of *Set.
+func (D *DisjointSet) Components() (C *Set) {
+	C = NewSet()
+	for _, b := range D.Partition() {
+		C.Add(b)
+	}
+	return
+}
+
+// Partition returns the blocks of D as a slice of *Set, ready to be passed to NewApproximationSpace.
+func (D *DisjointSet) Partition() []*Set {
+	blocks := make(map[interface{}]*Set, D.blocks)
+	P := make([]*Set, 0, D.blocks)
+	for e := range D.parent {
+		root, _ := D.Find(e)
+		b, ok := blocks[root]
+		if !ok {
+			b = NewSet()
+			blocks[root] = b
+			P = append(P, b)
+		}
+		b.Add(e)
+	}
+	return P
+}
+
+// ToPartition returns the blocks of D as a Partition of its elements. The blocks of D are
+// non-empty and disjoint by construction, so they are not checked as by NewPartition.
+func (D *DisjointSet) ToPartition() *Partition {
+	blocks := D.Partition()
+	P := &Partition{U: NewSet(), blocks: blocks, blockOf: make(map[interface{}]int, len(D.parent))}
+	for i, b := range blocks {
+		for e := range b.E {
+			P.U.E[e] = nothing{}
+			P.blockOf[e] = i
+		}
+	}
+	return P
+}
@@ -0,0 +1,82 @@
+package set
+
+import "testing"
+
+func Test_DisjointSetUnionFind(t *testing.T) {
+	D := NewDisjointSet(1, 2, 3, 4, 5)
+	if D.Blocks() != 5 {
+		t.Errorf("Expecting 5 blocks instead got %d", D.Blocks())
+	}
+	D.Union(1, 2)
+	D.Union(3, 4)
+	D.Union(2, 4)
+	if !D.Connected(1, 3) {
+		t.Error("Expecting 1 & 3 to be connected")
+	}
+	if D.Connected(1, 5) {
+		t.Error("Not expecting 1 & 5 to be connected")
+	}
+	if D.Connected(1, "missing") {
+		t.Error("Not expecting an element outside of D to be connected")
+	}
+	if D.Blocks() != 2 {
+		t.Errorf("Expecting 2 blocks instead got %d", D.Blocks())
+	}
+	r1, _ := D.Find(1)
+	r4, _ := D.Find(4)
+	if r1 != r4 {
+		t.Errorf("Expecting 1 & 4 to share a representative instead got %v & %v", r1, r4)
+	}
+	if _, ok := D.Find(6); ok {
+		t.Error("Not expecting to find an element outside of D")
+	}
+	D.Union(6, 7)
+	if D.Len() != 7 || D.Blocks() != 3 {
+		t.Errorf("Expecting Union to add missing elements, got %d elements in %d blocks", D.Len(), D.Blocks())
+	}
+	if B := D.Block(3); !B.IsEqual(NewSet(1, 2, 3, 4)) {
+		t.Errorf("Expecting the block of 3 to be {1, 2, 3, 4} instead got %v", B)
+	}
+}
+
+func Test_DisjointSetComponents(t *testing.T) {
+	D := NewDisjointSet()
+	for i := 0; i < 100; i++ {
+		D.Union(i, i%10)
+	}
+	C := D.Components()
+	if C.Cardinality() != 10 {
+		t.Errorf("Expecting 10 components instead got %f", C.Cardinality())
+	}
+	for b := range C.E {
+		B := b.(*Set)
+		if B.Cardinality() != 10 {
+			t.Errorf("Expecting every component to have 10 elements instead got %v", B)
+		}
+	}
+}
+
+func Test_DisjointSetPartition(t *testing.T) {
+	D := DisjointSetFromPartition(NewSet(1, 2), NewSet(3, 4), NewSet(4, 5), NewSet(6))
+	if D.Blocks() != 3 {
+		t.Errorf("Expecting overlapping blocks to merge into 3 blocks instead got %d", D.Blocks())
+	}
+	if !D.Connected(3, 5) || D.Connected(2, 3) {
+		t.Error("Expecting 3 & 5 to be connected and 2 & 3 not to be")
+	}
+	U := NewSet(1, 2, 3, 4, 5, 6)
+	if _, err := NewApproximationSpace(U, D.Partition()...); err != nil {
+		t.Errorf("Expecting the blocks to partition U instead got %v", err)
+	}
+	P := D.ToPartition()
+	if !P.U.IsEqual(U) || P.Len() != 3 {
+		t.Fatalf("Expecting a partition of U into 3 blocks instead got %v", P.Blocks())
+	}
+	if B, _ := P.Block(5); !B.IsEqual(NewSet(3, 4, 5)) {
+		t.Errorf("Expecting 5 to be in {3, 4, 5} instead got %v", B)
+	}
+	want, _ := NewPartition(U, NewSet(1, 2), NewSet(3, 4, 5), NewSet(6))
+	if ri, err := RandIndex(P, want); ri != 1 || err != nil {
+		t.Errorf("Expecting the partition to equal the blocks instead got a Rand index of %f, %v", ri, err)
+	}
+}
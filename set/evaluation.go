@@ -0,0 +1,221 @@
+package set
+
+import (
+	"math"
+	"sort"
+)
+
+// Confusion compares a predicted set with the true set: the true positives TP were predicted
+// and are true, the false positives FP were predicted but are not true, and the false
+// negatives FN are true but were not predicted.
+//
+// Measures that are 0/0, such as the precision of an empty prediction, are NaN unless opts say
+// otherwise, as for the similarity measures.
+type Confusion struct {
+	TP, FP, FN *Set
+}
+
+// NewConfusion returns the confusion breakdown of predicted against truth.
+func NewConfusion(predicted, truth Interface) Confusion {
+	return Confusion{
+		TP: Intersect(predicted, truth),
+		FP: Difference(predicted, truth),
+		FN: Difference(truth, predicted),
+	}
+}
+
+func (c Confusion) counts() (tp, fp, fn float64) {
+	return float64(c.TP.Len()), float64(c.FP.Len()), float64(c.FN.Len())
+}
+
+// Precision returns the fraction of the predicted elements that are true.
+//
+// P = TP / (TP + FP)
+func (c Confusion) Precision(opts ...SimilarityOption) float64 {
+	tp, fp, _ := c.counts()
+	return precision(tp, fp, opts)
+}
+
+// Recall returns the fraction of the true elements that were predicted.
+//
+// R = TP / (TP + FN)
+func (c Confusion) Recall(opts ...SimilarityOption) float64 {
+	tp, _, fn := c.counts()
+	return recall(tp, fn, opts)
+}
+
+// FScore returns the weighted harmonic mean of precision and recall, in which recall counts β
+// times as much as precision. β = 1 gives the F1 score, which equals DSC of the two sets.
+//
+// Fβ = (1+β²)·TP / ((1+β²)·TP + β²·FN + FP)
+func (c Confusion) FScore(beta float64, opts ...SimilarityOption) float64 {
+	tp, fp, fn := c.counts()
+	return fScore(beta, tp, fp, fn, opts)
+}
+
+// IoU returns the intersection over union of the predicted and true sets, their JaccardSimilarity.
+//
+// IoU = TP / (TP + FP + FN)
+func (c Confusion) IoU(opts ...SimilarityOption) float64 {
+	tp, fp, fn := c.counts()
+	return iou(tp, fp, fn, opts)
+}
+
+// PrecisionErr is Precision with an undefined precision reported as an error.
+func (c Confusion) PrecisionErr(opts ...SimilarityOption) (float64, error) {
+	return checked(opts, c.Precision)
+}
+
+// RecallErr is Recall with an undefined recall reported as an error.
+func (c Confusion) RecallErr(opts ...SimilarityOption) (float64, error) {
+	return checked(opts, c.Recall)
+}
+
+// FScoreErr is FScore with an undefined score reported as an error.
+func (c Confusion) FScoreErr(beta float64, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return c.FScore(beta, o...) })
+}
+
+// IoUErr is IoU with an undefined intersection over union reported as an error.
+func (c Confusion) IoUErr(opts ...SimilarityOption) (float64, error) {
+	return checked(opts, c.IoU)
+}
+
+func precision(tp, fp float64, opts []SimilarityOption) float64 {
+	if tp+fp == 0 {
+		return undefined("Precision", math.NaN(), opts)
+	}
+	return tp / (tp + fp)
+}
+
+func recall(tp, fn float64, opts []SimilarityOption) float64 {
+	if tp+fn == 0 {
+		return undefined("Recall", math.NaN(), opts)
+	}
+	return tp / (tp + fn)
+}
+
+func fScore(beta, tp, fp, fn float64, opts []SimilarityOption) float64 {
+	b2 := beta * beta
+	if den := (1+b2)*tp + b2*fn + fp; den != 0 {
+		return (1 + b2) * tp / den
+	}
+	return undefined("FScore", math.NaN(), opts)
+}
+
+func iou(tp, fp, fn float64, opts []SimilarityOption) float64 {
+	if tp+fp+fn == 0 {
+		return undefined("IoU", math.NaN(), opts)
+	}
+	return tp / (tp + fp + fn)
+}
+
+// Averaging is the way an Evaluation combines a measure over many comparisons.
+type Averaging int
+
+const (
+	// Micro pools the counts of every comparison and computes the measure once, so that every
+	// element counts equally.
+	Micro Averaging = iota
+	// Macro is the mean of the measure over the comparisons, so that every comparison counts equally.
+	Macro
+	// Weighted is the mean of the measure over the comparisons weighted by the size of the true set.
+	Weighted
+)
+
+// Evaluation is the confusion breakdown of a collection of (predicted, truth) pairs.
+type Evaluation []Confusion
+
+// Evaluate compares each set of predicted with the set of truth under the same name, in sorted
+// order of names. A name missing from either map is compared with the empty set.
+func Evaluate(predicted, truth map[string]Interface) Evaluation {
+	names := make([]string, 0, len(truth))
+	for n := range truth {
+		names = append(names, n)
+	}
+	for n := range predicted {
+		if _, ok := truth[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	E := make(Evaluation, len(names))
+	for i, n := range names {
+		P, T := predicted[n], truth[n]
+		if P == nil {
+			P = NewSet()
+		}
+		if T == nil {
+			T = NewSet()
+		}
+		E[i] = NewConfusion(P, T)
+	}
+	return E
+}
+
+// average combines the measure f of the counts of each comparison in E.
+func (E Evaluation) average(avg Averaging, measure string, f func(tp, fp, fn float64) float64, opts []SimilarityOption) float64 {
+	var tp, fp, fn, sum, weights float64
+	for _, c := range E {
+		t, p, n := c.counts()
+		tp, fp, fn = tp+t, fp+p, fn+n
+		switch avg {
+		case Macro:
+			sum += f(t, p, n)
+			weights++
+		case Weighted:
+			// Comparisons with an empty true set have no weight, and are left out.
+			if w := t + n; w > 0 {
+				sum += w * f(t, p, n)
+				weights += w
+			}
+		}
+	}
+	if avg == Micro {
+		return f(tp, fp, fn)
+	}
+	if weights == 0 {
+		return undefined(measure, math.NaN(), opts)
+	}
+	return sum / weights
+}
+
+// Precision returns the precision of E averaged by avg.
+func (E Evaluation) Precision(avg Averaging, opts ...SimilarityOption) float64 {
+	return E.average(avg, "Precision", func(tp, fp, fn float64) float64 { return precision(tp, fp, opts) }, opts)
+}
+
+// Recall returns the recall of E averaged by avg.
+func (E Evaluation) Recall(avg Averaging, opts ...SimilarityOption) float64 {
+	return E.average(avg, "Recall", func(tp, fp, fn float64) float64 { return recall(tp, fn, opts) }, opts)
+}
+
+// FScore returns the Fβ score of E averaged by avg.
+func (E Evaluation) FScore(beta float64, avg Averaging, opts ...SimilarityOption) float64 {
+	return E.average(avg, "FScore", func(tp, fp, fn float64) float64 { return fScore(beta, tp, fp, fn, opts) }, opts)
+}
+
+// IoU returns the intersection over union of E averaged by avg.
+func (E Evaluation) IoU(avg Averaging, opts ...SimilarityOption) float64 {
+	return E.average(avg, "IoU", func(tp, fp, fn float64) float64 { return iou(tp, fp, fn, opts) }, opts)
+}
+
+// PrecisionErr is Precision with an undefined average reported as an error.
+func (E Evaluation) PrecisionErr(avg Averaging, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return E.Precision(avg, o...) })
+}
+
+// RecallErr is Recall with an undefined average reported as an error.
+func (E Evaluation) RecallErr(avg Averaging, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return E.Recall(avg, o...) })
+}
+
+// FScoreErr is FScore with an undefined average reported as an error.
+func (E Evaluation) FScoreErr(beta float64, avg Averaging, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return E.FScore(beta, avg, o...) })
+}
+
+// IoUErr is IoU with an undefined average reported as an error.
+func (E Evaluation) IoUErr(avg Averaging, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return E.IoU(avg, o...) })
+}
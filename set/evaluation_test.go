@@ -0,0 +1,92 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_Confusion(t *testing.T) {
+	P := NewSet(1, 2, 3, 4)
+	T := NewSet(2, 3, 5)
+	c := NewConfusion(P, T)
+	if !c.TP.IsEqual(NewSet(2, 3)) || !c.FP.IsEqual(NewSet(1, 4)) || !c.FN.IsEqual(NewSet(5)) {
+		t.Errorf("Expecting TP {2,3}, FP {1,4} and FN {5} instead got %v, %v, %v", c.TP, c.FP, c.FN)
+	}
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"precision", c.Precision(), 0.5},
+		{"recall", c.Recall(), 2.0 / 3},
+		{"F1", c.FScore(1), 4.0 / 7},
+		{"F1 = DSC", c.FScore(1), DSC(P, T)},
+		{"F2", c.FScore(2), 0.625},
+		{"IoU", c.IoU(), JaccardSimilarity(P, T)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+	empty := NewConfusion(NewSet(), NewSet())
+	if !math.IsNaN(empty.Precision()) || empty.Recall(EmptyAsOne()) != 1 || empty.IoU(EmptyAsZero()) != 0 {
+		t.Error("Expecting undefined measures to follow the options")
+	}
+}
+
+func Test_Evaluation(t *testing.T) {
+	E := Evaluate(
+		map[string]Interface{"a": NewSet(1, 2, 3, 4), "b": NewSmallSet(1), "c": NewSet(7, 8)},
+		map[string]Interface{"a": NewSet(2, 3, 5), "b": NewSet(1, 2, 3, 4, 5, 6)},
+	)
+	if len(E) != 3 || E[2].FP.Len() != 2 {
+		t.Fatalf("Expecting c to be compared with the empty set instead got %v", E)
+	}
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"micro precision", E.Precision(Micro), 3.0 / 7},
+		{"micro recall", E.Recall(Micro), 1.0 / 3},
+		{"micro F1", E.FScore(1, Micro), 0.375},
+		{"micro IoU", E.IoU(Micro), 3.0 / 13},
+		{"macro precision", E.Precision(Macro), 0.5},
+		{"macro recall", E.Recall(Macro, EmptyAsZero()), 5.0 / 18},
+		{"macro F1", E.FScore(1, Macro), (4.0/7 + 2.0/7) / 3},
+		{"macro IoU", E.IoU(Macro), (0.4 + 1.0/6) / 3},
+		{"weighted precision", E.Precision(Weighted), 5.0 / 6},
+		{"weighted recall", E.Recall(Weighted), 1.0 / 3},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+	if r := E.Recall(Macro); !math.IsNaN(r) {
+		t.Errorf("Expecting an undefined recall to make the macro average NaN instead got %f", r)
+	}
+	if _, err := E.RecallErr(Macro); !errors.Is(err, ErrUndefinedSimilarity) {
+		t.Errorf("Expecting an undefined recall to be an error instead got %v", err)
+	}
+	if r, err := E.RecallErr(Micro); err != nil || math.Abs(r-1.0/3) > 1e-12 {
+		t.Errorf("Expecting a micro recall of 1/3 instead got %f, %v", r, err)
+	}
+	if _, err := Evaluation(nil).PrecisionErr(Macro); !errors.Is(err, ErrUndefinedSimilarity) {
+		t.Errorf("Expecting an error for the average of no comparisons instead got %v", err)
+	}
+	c := NewConfusion(NewSet(), NewSet())
+	for name, f := range map[string]func(...SimilarityOption) (float64, error){
+		"Precision": c.PrecisionErr,
+		"Recall":    c.RecallErr,
+		"FScore":    func(o ...SimilarityOption) (float64, error) { return c.FScoreErr(1, o...) },
+		"IoU":       c.IoUErr,
+	} {
+		if _, err := f(); !errors.Is(err, ErrUndefinedSimilarity) {
+			t.Errorf("Expecting %s of two empty sets to be an error instead got %v", name, err)
+		}
+		if v, err := f(EmptyAsOne()); v != 1 || err != nil {
+			t.Errorf("Expecting %s of two empty sets to be 1 with EmptyAsOne instead got %f, %v", name, v, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package set
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrEmptyFamily is returned for the intersection of a family with no members, which would be the whole universe.
+var ErrEmptyFamily = errors.New("set: intersection of an empty family")
+
+// Family is an indexed family of sets (Aᵢ)ᵢ∈I, indexed by position. Unlike a set of sets, a
+// family may hold the same set more than once.
+type Family []Interface
+
+// BigUnion returns the set of the elements of any member of F, built without intermediate
+// sets. The union of the empty family is ∅.
+//
+// ⋃ᵢ Aᵢ = {x : x ∈ Aᵢ for some i}
+func (F Family) BigUnion() *Set {
+	n := 0
+	for _, A := range F {
+		n += A.Len()
+	}
+	C := &Set{E: make(elements, n)}
+	for _, A := range F {
+		addAll(C, A)
+	}
+	return C
+}
+
+// BigIntersection returns the set of the elements of every member of F. It starts from the
+// smallest member and tests its elements against the others in order of size, so it costs
+// O(min|Aᵢ| · |F|) at most and stops as soon as the intersection is empty.
+// ErrEmptyFamily is returned if F has no members.
+//
+// ⋂ᵢ Aᵢ = {x : x ∈ Aᵢ for every i}
+func (F Family) BigIntersection() (*Set, error) {
+	if len(F) == 0 {
+		return nil, ErrEmptyFamily
+	}
+	bySize := make(Family, len(F))
+	copy(bySize, F)
+	sort.Slice(bySize, func(i, j int) bool { return bySize[i].Len() < bySize[j].Len() })
+	C := NewSet()
+	addAll(C, bySize[0])
+	for _, A := range bySize[1:] {
+		if C.Len() == 0 {
+			break
+		}
+		for e := range C.E {
+			if !A.Contains(e) {
+				delete(C.E, e)
+			}
+		}
+	}
+	return C, nil
+}
+
+// IsPairwiseDisjoint reports whether no element is in more than one member of F.
+//
+// Aᵢ ∩ Aⱼ = ∅ for all i ≠ j
+func (F Family) IsPairwiseDisjoint() bool {
+	seen := make(map[interface{}]nothing)
+	disjoint := true
+	for _, A := range F {
+		A.Iterate(func(e interface{}) bool {
+			if _, ok := seen[e]; ok {
+				disjoint = false
+			}
+			seen[e] = nothing{}
+			return disjoint
+		})
+		if !disjoint {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCoverOf reports whether the members of F are subsets of U whose union is U.
+//
+// ⋃ᵢ Aᵢ = U
+func (F Family) IsCoverOf(U Interface) bool {
+	covered := make(map[interface{}]nothing, U.Len())
+	subsets := true
+	for _, A := range F {
+		A.Iterate(func(e interface{}) bool {
+			subsets = U.Contains(e)
+			covered[e] = nothing{}
+			return subsets
+		})
+		if !subsets {
+			return false
+		}
+	}
+	return len(covered) == U.Len()
+}
+
+// IsPartitionOf reports whether the members of F are non-empty, pairwise disjoint and cover U,
+// as the blocks accepted by NewPartition.
+func (F Family) IsPartitionOf(U Interface) bool {
+	_, err := blockIndex(U, F)
+	return err == nil
+}
+
+// multiplicities returns the number of members of F holding each element of their union.
+func (F Family) multiplicities() map[interface{}]int {
+	m := make(map[interface{}]int)
+	for _, A := range F {
+		A.Iterate(func(e interface{}) bool {
+			m[e]++
+			return true
+		})
+	}
+	return m
+}
+
+// CountExactly returns the number of elements in exactly k members of F. For k = 1 it is the
+// size of the generalised symmetric difference; for k = |F|, of the intersection. Without a
+// universe no element is in no member, so k < 1 gives 0.
+//
+// |{x : |{i : x ∈ Aᵢ}| = k}|
+func (F Family) CountExactly(k int) (n int) {
+	if k < 1 {
+		return 0
+	}
+	for _, c := range F.multiplicities() {
+		if c == k {
+			n++
+		}
+	}
+	return
+}
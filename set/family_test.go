@@ -0,0 +1,103 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_FamilyUnionIntersection(t *testing.T) {
+	F := Family{NewSet(1, 2, 3, 4), NewSet(2, 3, 5), NewSet(3, 2, 6, 7, 8)}
+	if U := F.BigUnion(); !U.IsEqual(NewSet(1, 2, 3, 4, 5, 6, 7, 8)) {
+		t.Errorf("Expecting the union {1,…,8} instead got %v", U)
+	}
+	I, err := F.BigIntersection()
+	if err != nil || !I.IsEqual(NewSet(2, 3)) {
+		t.Errorf("Expecting the intersection {2,3} instead got %v, %v", I, err)
+	}
+	if F[1].Len() != 3 {
+		t.Errorf("Expecting the members to be left alone instead got %v", F)
+	}
+	if I, _ := append(F, NewSet(9)).BigIntersection(); I.Len() != 0 {
+		t.Errorf("Expecting an empty intersection instead got %v", I)
+	}
+	if U := (Family{}).BigUnion(); U.Len() != 0 {
+		t.Errorf("Expecting the empty union to be ∅ instead got %v", U)
+	}
+	if _, err := (Family{}).BigIntersection(); err != ErrEmptyFamily {
+		t.Errorf("Expecting ErrEmptyFamily instead got %v", err)
+	}
+
+	// Folding the binary operations must agree.
+	r := rand.New(rand.NewSource(49))
+	for trial := 0; trial < 20; trial++ {
+		F := make(Family, 1+r.Intn(5))
+		for i := range F {
+			F[i] = NewSet()
+			for j := r.Intn(30); j > 0; j-- {
+				F[i].Add(r.Intn(20))
+			}
+		}
+		U, I := F[0], F[0]
+		for _, A := range F[1:] {
+			U, I = Union(U, A), Intersect(I, A)
+		}
+		if got := F.BigUnion(); !got.IsEqual(U) {
+			t.Errorf("Expecting %v instead got %v", U, got)
+		}
+		if got, _ := F.BigIntersection(); !got.IsEqual(I) {
+			t.Errorf("Expecting %v instead got %v", I, got)
+		}
+	}
+}
+
+func Test_FamilyCoverPartition(t *testing.T) {
+	U := NewSet(1, 2, 3, 4, 5)
+	for _, c := range []struct {
+		F                          Family
+		disjoint, cover, partition bool
+	}{
+		{Family{NewSet(1, 2), NewSet(3), NewSet(4, 5)}, true, true, true},
+		{Family{NewSet(1, 2, 3), NewSet(3, 4, 5)}, false, true, false},
+		{Family{NewSet(1, 2), NewSet(4, 5)}, true, false, false},
+		{Family{NewSet(1, 2), NewSet(3, 4, 5, 6)}, true, false, false},
+		{Family{NewSet(1, 2, 3), NewSet(), NewSet(4, 5)}, true, true, false},
+		{Family{NewSet(1, 2, 3, 4, 5), NewSet(1, 2, 3, 4, 5)}, false, true, false},
+		{Family{}, true, false, false},
+	} {
+		if got := c.F.IsPairwiseDisjoint(); got != c.disjoint {
+			t.Errorf("Expecting IsPairwiseDisjoint of %v to be %v", c.F, c.disjoint)
+		}
+		if got := c.F.IsCoverOf(U); got != c.cover {
+			t.Errorf("Expecting IsCoverOf of %v to be %v", c.F, c.cover)
+		}
+		if got := c.F.IsPartitionOf(U); got != c.partition {
+			t.Errorf("Expecting IsPartitionOf of %v to be %v", c.F, c.partition)
+		}
+		blocks := make([]*Set, len(c.F))
+		for i, A := range c.F {
+			blocks[i] = A.(*Set)
+		}
+		if _, err := NewPartition(U, blocks...); (err == nil) != c.partition {
+			t.Errorf("Expecting NewPartition of %v to agree with IsPartitionOf instead got %v", c.F, err)
+		}
+	}
+	if !(Family{}).IsPartitionOf(NewSet()) {
+		t.Error("Expecting the empty family to partition ∅")
+	}
+	if F := (Family{NewSmallSet(1, 2), NewSet(3, 4, 5)}); !F.IsPartitionOf(U) || F.CountExactly(1) != 5 {
+		t.Error("Expecting a family of any set type to partition U")
+	}
+}
+
+func Test_FamilyCountExactly(t *testing.T) {
+	F := Family{NewSet(1, 2, 3, 4), NewSet(2, 3, 5), NewSet(3, 2, 6, 7, 8)}
+	for k, want := range []int{0, 6, 0, 2, 0} {
+		if got := F.CountExactly(k); got != want {
+			t.Errorf("Expecting %d elements in exactly %d members instead got %d", want, k, got)
+		}
+	}
+	sym := SymetricDifferencec(F[0], F[1])
+	if got := F[:2].CountExactly(1); got != sym.Len() {
+		t.Errorf("Expecting the size of the symmetric difference %v instead got %d", sym, got)
+	}
+}
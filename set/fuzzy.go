@@ -0,0 +1,276 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrMembershipOutOfRange is returned when a membership degree is outside of [0,1].
+var ErrMembershipOutOfRange = errors.New("set: membership degree must be in [0,1]")
+
+// TNorm is a triangular norm used to generalise intersection to fuzzy sets.
+//
+// T(a,1)=a, T(a,b)=T(b,a), T(a,T(b,c))=T(T(a,b),c) and T is monotone in each argument.
+type TNorm func(a, b float64) float64
+
+// TConorm is a triangular conorm used to generalise union to fuzzy sets.
+//
+// S(a,0)=a, S(a,b)=S(b,a), S(a,S(b,c))=S(S(a,b),c) and S is monotone in each argument.
+type TConorm func(a, b float64) float64
+
+// MinimumTNorm is the Gödel (standard) t-norm.
+// T(a,b)=min(a,b)
+func MinimumTNorm(a, b float64) float64 {
+	return math.Min(a, b)
+}
+
+// ProductTNorm is the algebraic product t-norm.
+// T(a,b)=a·b
+func ProductTNorm(a, b float64) float64 {
+	return a * b
+}
+
+// LukasiewiczTNorm is the Łukasiewicz (bounded difference) t-norm.
+// T(a,b)=max(0,a+b-1)
+func LukasiewiczTNorm(a, b float64) float64 {
+	return math.Max(0, a+b-1)
+}
+
+// MaximumTConorm is the Gödel (standard) t-conorm, dual to MinimumTNorm.
+// S(a,b)=max(a,b)
+func MaximumTConorm(a, b float64) float64 {
+	return math.Max(a, b)
+}
+
+// ProbabilisticSum is the t-conorm dual to ProductTNorm.
+// S(a,b)=a+b-a·b
+func ProbabilisticSum(a, b float64) float64 {
+	return a + b - a*b
+}
+
+// LukasiewiczTConorm is the Łukasiewicz (bounded sum) t-conorm, dual to LukasiewiczTNorm.
+// S(a,b)=min(1,a+b)
+func LukasiewiczTConorm(a, b float64) float64 {
+	return math.Min(1, a+b)
+}
+
+// FuzzySet is a set in which every element carries a degree of membership in [0,1].
+// Elements with a membership of 0 are not stored.
+//
+// μA(x)	membership function	the degree to which x belongs to A	μA(x)∈[0,1]
+type FuzzySet struct {
+	m map[interface{}]float64
+}
+
+// NewFuzzySet returns a new, empty fuzzy set.
+func NewFuzzySet() *FuzzySet {
+	return &FuzzySet{m: make(map[interface{}]float64)}
+}
+
+// FuzzySetFromSet returns the fuzzy set in which every element of A has a membership of 1.
+func FuzzySetFromSet(A Interface) *FuzzySet {
+	F := &FuzzySet{m: make(map[interface{}]float64, A.Len())}
+	A.Iterate(func(e interface{}) bool {
+		F.m[e] = 1
+		return true
+	})
+	return F
+}
+
+// Add sets the membership of x in A to degree. A degree of 0 removes x.
+func (A *FuzzySet) Add(x interface{}, degree float64) error {
+	if math.IsNaN(degree) || degree < 0 || degree > 1 {
+		return ErrMembershipOutOfRange
+	}
+	A.set(x, degree)
+	return nil
+}
+
+func (A *FuzzySet) set(x interface{}, degree float64) {
+	if degree == 0 {
+		delete(A.m, x)
+		return
+	}
+	A.m[x] = degree
+}
+
+// Remove deletes one or more elements from A.
+func (A *FuzzySet) Remove(els ...interface{}) {
+	for _, e := range els {
+		delete(A.m, e)
+	}
+}
+
+// Membership returns the degree to which x belongs to A.
+func (A *FuzzySet) Membership(x interface{}) float64 {
+	return A.m[x]
+}
+
+// Cardinality returns the sigma count of A, the sum of all membership degrees.
+//
+// |A| = Σ μA(x)
+func (A *FuzzySet) Cardinality() float64 {
+	var sum float64
+	for _, d := range A.m {
+		sum += d
+	}
+	return sum
+}
+
+// String returns a string representation of A.
+func (A *FuzzySet) String() (s string) {
+	els := make([]string, 0, len(A.m))
+	for e, d := range A.m {
+		els = append(els, fmt.Sprintf("%v/%v", d, e))
+	}
+	s = fmt.Sprintf("{%v}", strings.Join(els, ", "))
+	return
+}
+
+// AlphaCut returns the crisp set of elements with a membership of at least alpha.
+//
+// Aα = {x : μA(x) ≥ α}
+func (A *FuzzySet) AlphaCut(alpha float64) *Set {
+	C := NewSet()
+	for e, d := range A.m {
+		if d >= alpha {
+			C.Add(e)
+		}
+	}
+	return C
+}
+
+// StrongAlphaCut returns the crisp set of elements with a membership greater than alpha.
+//
+// Aα+ = {x : μA(x) > α}
+func (A *FuzzySet) StrongAlphaCut(alpha float64) *Set {
+	C := NewSet()
+	for e, d := range A.m {
+		if d > alpha {
+			C.Add(e)
+		}
+	}
+	return C
+}
+
+// Support returns the crisp set of elements with a non-zero membership.
+//
+// supp(A) = {x : μA(x) > 0}
+func (A *FuzzySet) Support() *Set {
+	return A.StrongAlphaCut(0)
+}
+
+// Core returns the crisp set of elements that fully belong to A.
+//
+// core(A) = {x : μA(x) = 1}
+func (A *FuzzySet) Core() *Set {
+	return A.AlphaCut(1)
+}
+
+// Union creates a new fuzzy set (C) from A & B using the t-conorm s.
+// When s is nil the standard union (MaximumTConorm) is used.
+//
+// μA∪B(x) = S(μA(x), μB(x))
+func (A *FuzzySet) Union(B *FuzzySet, s TConorm) *FuzzySet {
+	return FuzzyUnion(A, B, s)
+}
+
+// Intersect creates a new fuzzy set (C) from A & B using the t-norm t.
+// When t is nil the standard intersection (MinimumTNorm) is used.
+//
+// μA∩B(x) = T(μA(x), μB(x))
+func (A *FuzzySet) Intersect(B *FuzzySet, t TNorm) *FuzzySet {
+	return FuzzyIntersect(A, B, t)
+}
+
+// Complement returns the standard complement of A within the universe U.
+// Elements of A that are not in U are also considered part of the universe.
+//
+// μA'(x) = 1 - μA(x)
+func (A *FuzzySet) Complement(U *Set) *FuzzySet {
+	return FuzzyComplement(A, U)
+}
+
+// FuzzyUnion creates a new fuzzy set (C) from A & B using the t-conorm s.
+// When s is nil the standard union (MaximumTConorm) is used.
+//
+// μA∪B(x) = S(μA(x), μB(x))
+func FuzzyUnion(A, B *FuzzySet, s TConorm) (C *FuzzySet) {
+	if s == nil {
+		s = MaximumTConorm
+	}
+	C = NewFuzzySet()
+	for e, d := range A.m {
+		C.set(e, s(d, B.m[e]))
+	}
+	for e, d := range B.m {
+		if _, ok := A.m[e]; !ok {
+			C.set(e, s(0, d))
+		}
+	}
+	return
+}
+
+// FuzzyIntersect creates a new fuzzy set (C) from A & B using the t-norm t.
+// When t is nil the standard intersection (MinimumTNorm) is used.
+//
+// μA∩B(x) = T(μA(x), μB(x))
+func FuzzyIntersect(A, B *FuzzySet, t TNorm) (C *FuzzySet) {
+	if t == nil {
+		t = MinimumTNorm
+	}
+	C = NewFuzzySet()
+	for e, d := range A.m {
+		if d2, ok := B.m[e]; ok {
+			C.set(e, t(d, d2))
+		}
+	}
+	return
+}
+
+// FuzzyComplement returns the standard complement of A within the universe U.
+// Elements of A that are not in U are also considered part of the universe.
+//
+// μA'(x) = 1 - μA(x)
+func FuzzyComplement(A *FuzzySet, U *Set) (C *FuzzySet) {
+	C = NewFuzzySet()
+	for e := range U.E {
+		C.set(e, 1-A.m[e])
+	}
+	for e, d := range A.m {
+		C.set(e, 1-d)
+	}
+	return
+}
+
+// FuzzyJaccardSimilarity
+// The fuzzy Jaccard index generalises JaccardSimilarity to membership degrees.
+// When every membership is 0 or 1 it is equal to JaccardSimilarity of the supports.
+//
+// J(A,B) = Σ min(μA(x), μB(x)) / Σ max(μA(x), μB(x))
+//
+// It is undefined, NaN unless opts say otherwise, when A & B are both empty.
+func FuzzyJaccardSimilarity(A, B *FuzzySet, opts ...SimilarityOption) float64 {
+	var intersection, union float64
+	for e, d := range A.m {
+		d2 := B.m[e]
+		intersection += math.Min(d, d2)
+		union += math.Max(d, d2)
+	}
+	for e, d := range B.m {
+		if _, ok := A.m[e]; !ok {
+			union += d
+		}
+	}
+	if union == 0 {
+		return undefined("FuzzyJaccardSimilarity", math.NaN(), opts)
+	}
+	return intersection / union
+}
+
+// FuzzyJaccardSimilarityErr is FuzzyJaccardSimilarity with an undefined index reported as an error.
+func FuzzyJaccardSimilarityErr(A, B *FuzzySet, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return FuzzyJaccardSimilarity(A, B, o...) })
+}
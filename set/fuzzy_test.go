@@ -0,0 +1,111 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+func newFuzzy(t *testing.T, pairs ...interface{}) *FuzzySet {
+	t.Helper()
+	A := NewFuzzySet()
+	for i := 0; i < len(pairs); i += 2 {
+		if err := A.Add(pairs[i], pairs[i+1].(float64)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return A
+}
+
+func Test_FuzzyAdd(t *testing.T) {
+	A := NewFuzzySet()
+	if err := A.Add("a", 1.5); err != ErrMembershipOutOfRange {
+		t.Errorf("Expecting ErrMembershipOutOfRange instead got %v", err)
+	}
+	if err := A.Add("a", -0.1); err != ErrMembershipOutOfRange {
+		t.Errorf("Expecting ErrMembershipOutOfRange instead got %v", err)
+	}
+	if err := A.Add("a", 0.5); err != nil {
+		t.Errorf("Expecting no error instead got %v", err)
+	}
+	if A.Membership("a") != 0.5 {
+		t.Errorf("Expecting a membership of 0.5 instead got %f", A.Membership("a"))
+	}
+	_ = A.Add("a", 0)
+	if A.Support().Cardinality() != 0 {
+		t.Errorf("Expecting a membership of 0 to remove the element, found %v", A)
+	}
+}
+
+func Test_FuzzyCuts(t *testing.T) {
+	A := newFuzzy(t, 1, 0.2, 2, 0.5, 3, 1.0, 4, 0.7)
+	if C := A.AlphaCut(0.5); !C.IsEqual(NewSet(2, 3, 4)) {
+		t.Errorf("Expecting the 0.5 cut to be {2, 3, 4} instead got %v", C)
+	}
+	if C := A.StrongAlphaCut(0.5); !C.IsEqual(NewSet(3, 4)) {
+		t.Errorf("Expecting the strong 0.5 cut to be {3, 4} instead got %v", C)
+	}
+	if C := A.Support(); !C.IsEqual(NewSet(1, 2, 3, 4)) {
+		t.Errorf("Expecting the support to be {1, 2, 3, 4} instead got %v", C)
+	}
+	if C := A.Core(); !C.IsEqual(NewSet(3)) {
+		t.Errorf("Expecting the core to be {3} instead got %v", C)
+	}
+	if c := A.Cardinality(); math.Abs(c-2.4) > 1e-9 {
+		t.Errorf("Expecting a sigma count of 2.4 instead got %f", c)
+	}
+}
+
+func Test_FuzzyUnionIntersect(t *testing.T) {
+	A := newFuzzy(t, "x", 0.6, "y", 0.3)
+	B := newFuzzy(t, "x", 0.5, "z", 0.8)
+	tests := []struct {
+		name       string
+		t          TNorm
+		s          TConorm
+		ix, ux, uy float64
+	}{
+		{"standard", MinimumTNorm, MaximumTConorm, 0.5, 0.6, 0.3},
+		{"product", ProductTNorm, ProbabilisticSum, 0.3, 0.8, 0.3},
+		{"lukasiewicz", LukasiewiczTNorm, LukasiewiczTConorm, 0.1, 1, 0.3},
+	}
+	for _, tt := range tests {
+		I := A.Intersect(B, tt.t)
+		U := A.Union(B, tt.s)
+		if math.Abs(I.Membership("x")-tt.ix) > 1e-9 {
+			t.Errorf("%s: expecting μ(x) of the intersection to be %f instead got %f", tt.name, tt.ix, I.Membership("x"))
+		}
+		if I.Membership("y") != 0 || I.Membership("z") != 0 {
+			t.Errorf("%s: expecting only x in the intersection instead got %v", tt.name, I)
+		}
+		if math.Abs(U.Membership("x")-tt.ux) > 1e-9 {
+			t.Errorf("%s: expecting μ(x) of the union to be %f instead got %f", tt.name, tt.ux, U.Membership("x"))
+		}
+		if math.Abs(U.Membership("y")-tt.uy) > 1e-9 || U.Membership("z") != 0.8 {
+			t.Errorf("%s: expecting y and z to keep their memberships in the union instead got %v", tt.name, U)
+		}
+	}
+	if I := FuzzyIntersect(A, B, nil); I.Membership("x") != 0.5 {
+		t.Errorf("Expecting the default intersection to use the minimum, got %v", I)
+	}
+}
+
+func Test_FuzzyComplement(t *testing.T) {
+	A := newFuzzy(t, 1, 0.25, 2, 1.0)
+	C := A.Complement(NewSet(1, 2, 3))
+	if C.Membership(1) != 0.75 || C.Membership(2) != 0 || C.Membership(3) != 1 {
+		t.Errorf("Expecting the complement {0.75/1, 1/3} instead got %v", C)
+	}
+}
+
+func Test_FuzzyJaccardSimilarity(t *testing.T) {
+	A := NewSet(0, 1, 2, 5, 6, 8, 9)
+	B := NewSet(0, 2, 3, 4, 5, 7, 9)
+	if j := FuzzyJaccardSimilarity(FuzzySetFromSet(A), FuzzySetFromSet(B)); j != JaccardSimilarity(A, B) {
+		t.Errorf("Expecting the fuzzy index of crisp sets to equal JaccardSimilarity (%f) instead got %f", JaccardSimilarity(A, B), j)
+	}
+	C := newFuzzy(t, "a", 0.5, "b", 1.0)
+	D := newFuzzy(t, "a", 1.0, "c", 0.5)
+	if j := FuzzyJaccardSimilarity(C, D); math.Abs(j-0.2) > 1e-9 {
+		t.Errorf("Expecting a fuzzy similarity of 0.2 instead got %f", j)
+	}
+}
@@ -0,0 +1,102 @@
+package set
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// hashElement returns a 64-bit hash of e for the given seed. Unlike a Go map hash it is stable
+// across processes, so filters and sketches built from it can be serialised and merged later.
+// Elements that are distinct in a Set, such as int(1) and int64(1), hash differently.
+//
+// Sets used as elements, such as the members of a Powerset, hash by their elements whatever
+// the order they are stored in, so equal sets hash alike. Other types hash by their Go-syntax
+// representation (%#v), which ignores String methods: values hash by their fields, and pointers
+// below the top level by address, so such hashes are stable only within a process. An element
+// must not be changed after it is added to a filter or sketch.
+func hashElement(e interface{}, seed uint64) uint64 {
+	h := fnvUint64(fnvOffset64, seed)
+	h = fnvValue(h, e)
+	return mix64(h)
+}
+
+func fnvValue(h uint64, e interface{}) uint64 {
+	switch v := e.(type) {
+	case string:
+		return fnvString(fnvByte(h, 's'), v)
+	case int:
+		return fnvUint64(fnvByte(h, 'i'), uint64(v))
+	case int8:
+		return fnvUint64(fnvByte(h, 1), uint64(v))
+	case int16:
+		return fnvUint64(fnvByte(h, 2), uint64(v))
+	case int32:
+		return fnvUint64(fnvByte(h, 3), uint64(v))
+	case int64:
+		return fnvUint64(fnvByte(h, 4), uint64(v))
+	case uint:
+		return fnvUint64(fnvByte(h, 'u'), uint64(v))
+	case uint8:
+		return fnvUint64(fnvByte(h, 5), uint64(v))
+	case uint16:
+		return fnvUint64(fnvByte(h, 6), uint64(v))
+	case uint32:
+		return fnvUint64(fnvByte(h, 7), uint64(v))
+	case uint64:
+		return fnvUint64(fnvByte(h, 8), v)
+	case float32:
+		return fnvUint64(fnvByte(h, 9), uint64(math.Float32bits(v)))
+	case float64:
+		return fnvUint64(fnvByte(h, 'f'), math.Float64bits(v))
+	case bool:
+		if v {
+			return fnvByte(fnvByte(h, 'b'), 1)
+		}
+		return fnvByte(fnvByte(h, 'b'), 0)
+	case Tuple:
+		return fnvValue(fnvValue(fnvByte(h, 't'), v.a), v.b)
+	case Interface:
+		// A sum of the mixed hashes of the elements does not depend on their order.
+		var sum uint64
+		v.Iterate(func(e interface{}) bool {
+			sum += mix64(fnvValue(fnvOffset64, e))
+			return true
+		})
+		return fnvUint64(fnvUint64(fnvByte(h, 'S'), uint64(v.Len())), sum)
+	default:
+		return fnvString(fnvByte(h, '?'), fmt.Sprintf("%T:%#v", e, e))
+	}
+}
+
+func fnvByte(h uint64, b byte) uint64 {
+	return (h ^ uint64(b)) * fnvPrime64
+}
+
+func fnvString(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h = fnvByte(h, s[i])
+	}
+	return h
+}
+
+func fnvUint64(h, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h = fnvByte(h, byte(v>>(8*i)))
+	}
+	return h
+}
+
+// mix64 is the splitmix64 finaliser. It spreads every input bit over the whole output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,53 @@
+package set
+
+import "testing"
+
+type hashPoint struct {
+	x, y int
+}
+
+func Test_HashElementStable(t *testing.T) {
+	A := NewSet(1, "a", 2.5, NewTuple(3, 4), NewSet(5, 6))
+	S := NewSmallSet(1, "a", 2.5, NewTuple(3, 4), NewSet(6, 5))
+	want := hashElement(A, 7)
+	for i := 0; i < 50; i++ {
+		if h := hashElement(A, 7); h != want {
+			t.Fatalf("Expecting the hash of %v to be stable instead got %x and %x", A, want, h)
+		}
+	}
+	if h := hashElement(S, 7); h != want {
+		t.Errorf("Expecting equal sets to hash alike instead got %x and %x", want, h)
+	}
+	if hashElement(NewSet(1, 2), 7) == hashElement(NewSet(1, 3), 7) {
+		t.Error("Expecting different sets to hash differently")
+	}
+	if hashElement(NewSet(), 7) == hashElement(NewSet(0), 7) {
+		t.Error("Expecting {} and {0} to hash differently")
+	}
+	if hashElement(hashPoint{1, 2}, 7) != hashElement(hashPoint{1, 2}, 7) || hashElement(hashPoint{1, 2}, 7) == hashElement(hashPoint{2, 1}, 7) {
+		t.Error("Expecting structs to hash by their fields")
+	}
+}
+
+func Test_FiltersOfSets(t *testing.T) {
+	P := NewSet(1, 2, 3, 4, 5, 6).Powerset()
+	bloom, err := BloomFilterFromSet(P, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cuckoo, err := CuckooFilterFromSet(P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xor, err := NewXorFilter(P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []Filter{bloom, cuckoo, xor} {
+		for e := range P.E {
+			if !f.MayContain(e) {
+				t.Fatalf("Expecting no false negatives from %T, %v is missing", f, e)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidCounts is returned when set sizes and an overlap could not come from sets in a universe of the given size.
+var ErrInvalidCounts = errors.New("set: counts are inconsistent with the universe size")
+
+// OverlapTest is the result of testing whether two sets drawn from a universe of N elements
+// share more, or fewer, elements than expected by chance. If B is a random subset of the
+// universe of its size, the overlap with A follows the hypergeometric distribution
+//
+// P(X = k) = C(|A|,k)·C(N−|A|,|B|−k) / C(N,|B|)
+//
+// which gives the p-values of the one- and two-sided Fisher exact test.
+type OverlapTest struct {
+	N, SizeA, SizeB, Overlap int
+
+	// Expected is the mean overlap, |A|·|B|/N.
+	Expected float64
+	// FoldEnrichment is the overlap divided by the expected overlap, NaN when none is expected.
+	FoldEnrichment float64
+	// PValue is the probability of an overlap at least as large, P(X ≥ k), the test for enrichment.
+	PValue float64
+	// DepletionPValue is the probability of an overlap at most as large, P(X ≤ k).
+	DepletionPValue float64
+	// TwoSidedPValue is the probability of an overlap no more likely than the one observed.
+	TwoSidedPValue float64
+}
+
+// HypergeometricTest tests the overlap of A & B as subsets of a universe of N elements.
+// ErrInvalidCounts is returned if A & B together have more than N elements.
+func HypergeometricTest(A, B Interface, N int) (OverlapTest, error) {
+	return HypergeometricTestCounts(N, A.Len(), B.Len(), intersectionLen(A, B))
+}
+
+// HypergeometricTestCounts tests an overlap of k between sets of sizes a & b in a universe of
+// N elements.
+// ErrInvalidCounts is returned unless the counts are those of two subsets of such a universe.
+func HypergeometricTestCounts(N, a, b, k int) (OverlapTest, error) {
+	if a < 0 || b < 0 || k < 0 || k > a || k > b || a+b-k > N {
+		return OverlapTest{}, ErrInvalidCounts
+	}
+	t := OverlapTest{N: N, SizeA: a, SizeB: b, Overlap: k}
+	if N > 0 {
+		t.Expected = float64(a) * float64(b) / float64(N)
+	}
+	t.FoldEnrichment = math.NaN()
+	if t.Expected > 0 {
+		t.FoldEnrichment = float64(k) / t.Expected
+	}
+	lo, hi := b-(N-a), b
+	if lo < 0 {
+		lo = 0
+	}
+	if a < hi {
+		hi = a
+	}
+	observed := hypergeometricLogPMF(N, a, b, k)
+	for i := lo; i <= hi; i++ {
+		lp := hypergeometricLogPMF(N, a, b, i)
+		p := math.Exp(lp)
+		if i >= k {
+			t.PValue += p
+		}
+		if i <= k {
+			t.DepletionPValue += p
+		}
+		// A relative tolerance keeps outcomes exactly as likely as the observed one despite rounding.
+		if lp <= observed+1e-7 {
+			t.TwoSidedPValue += p
+		}
+	}
+	t.PValue = math.Min(t.PValue, 1)
+	t.DepletionPValue = math.Min(t.DepletionPValue, 1)
+	t.TwoSidedPValue = math.Min(t.TwoSidedPValue, 1)
+	return t, nil
+}
+
+// hypergeometricLogPMF returns ln P(X = k).
+func hypergeometricLogPMF(N, a, b, k int) float64 {
+	return logChoose(a, k) + logChoose(N-a, b-k) - logChoose(N, b)
+}
+
+// logChoose returns ln C(n,k).
+func logChoose(n, k int) float64 {
+	x, _ := math.Lgamma(float64(n + 1))
+	y, _ := math.Lgamma(float64(k + 1))
+	z, _ := math.Lgamma(float64(n - k + 1))
+	return x - y - z
+}
+
+// Bonferroni adjusts the p-values of m tests for multiple testing by multiplying each by m,
+// which bounds the probability of any false positive (the family-wise error rate).
+//
+// p̃ᵢ = min(1, m·pᵢ)
+func Bonferroni(p []float64) []float64 {
+	adjusted := make([]float64, len(p))
+	for i, v := range p {
+		adjusted[i] = math.Min(1, v*float64(len(p)))
+	}
+	return adjusted
+}
+
+// BenjaminiHochberg adjusts the p-values of m tests for multiple testing so that rejecting those
+// with an adjusted value of at most q bounds the expected fraction of false positives among them
+// (the false discovery rate) by q. The values are returned in the order of p.
+//
+// p̃₍ᵢ₎ = min_{j≥i} min(1, m·p₍ⱼ₎/j), with p₍ᵢ₎ the i-th smallest
+func BenjaminiHochberg(p []float64) []float64 {
+	m := len(p)
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return p[order[i]] < p[order[j]] })
+	adjusted := make([]float64, m)
+	min := 1.0
+	for rank := m; rank >= 1; rank-- {
+		i := order[rank-1]
+		min = math.Min(min, p[i]*float64(m)/float64(rank))
+		adjusted[i] = min
+	}
+	return adjusted
+}
@@ -0,0 +1,72 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_HypergeometricTestCounts(t *testing.T) {
+	// Reference values were computed exactly with rational arithmetic.
+	tests := []struct {
+		N, a, b, k              int
+		greater, less, twoSided float64
+	}{
+		{8, 4, 4, 3, 17.0 / 70, 69.0 / 70, 34.0 / 70},
+		{20000, 300, 500, 20, 7.304009754512425e-05, 0.9999762376361971, 7.304009754512425e-05},
+		{20000, 300, 500, 2, 0.995819420874837, 0.018579172071249518, 0.037868588809422774},
+		{100, 10, 10, 10, 5.776904234533874e-14, 1, 5.776904234533874e-14},
+		{1000, 50, 60, 3, 0.5893340324046472, 0.6477079506489177, 1},
+	}
+	close := func(got, want float64) bool {
+		return math.Abs(got-want) <= 1e-9*want
+	}
+	for _, tt := range tests {
+		r, err := HypergeometricTestCounts(tt.N, tt.a, tt.b, tt.k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !close(r.PValue, tt.greater) || !close(r.DepletionPValue, tt.less) || !close(r.TwoSidedPValue, tt.twoSided) {
+			t.Errorf("%v: expecting p-values %g, %g, %g instead got %g, %g, %g", tt, tt.greater, tt.less, tt.twoSided,
+				r.PValue, r.DepletionPValue, r.TwoSidedPValue)
+		}
+	}
+	if _, err := HypergeometricTestCounts(10, 6, 6, 1); err != ErrInvalidCounts {
+		t.Errorf("Expecting ErrInvalidCounts instead got %v", err)
+	}
+}
+
+func Test_HypergeometricTest(t *testing.T) {
+	A := intSet(0, 300)
+	B := intSet(280, 780)
+	r, err := HypergeometricTest(A, B, 20000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Overlap != 20 || r.Expected != 7.5 || math.Abs(r.FoldEnrichment-20/7.5) > 1e-12 {
+		t.Errorf("Expecting an overlap of 20 against 7.5 expected instead got %d against %f", r.Overlap, r.Expected)
+	}
+	if math.Abs(r.PValue-7.304009754512425e-05) > 1e-13 {
+		t.Errorf("Expecting a p-value of 7.304e-05 instead got %g", r.PValue)
+	}
+	if r, _ := HypergeometricTest(NewSet(), A, 1000); !math.IsNaN(r.FoldEnrichment) || r.PValue != 1 {
+		t.Errorf("Expecting no enrichment to be measurable for an empty set instead got %f, %f", r.FoldEnrichment, r.PValue)
+	}
+}
+
+func Test_MultipleTestingCorrection(t *testing.T) {
+	p := []float64{0.01, 0.04, 0.03, 0.005, 0.2}
+	bonferroni := []float64{0.05, 0.2, 0.15, 0.025, 1}
+	bh := []float64{0.025, 0.05, 0.05, 0.025, 0.2}
+	for i, got := range Bonferroni(p) {
+		if math.Abs(got-bonferroni[i]) > 1e-12 {
+			t.Errorf("Bonferroni: expecting %v instead got %v", bonferroni, Bonferroni(p))
+			break
+		}
+	}
+	for i, got := range BenjaminiHochberg(p) {
+		if math.Abs(got-bh[i]) > 1e-12 {
+			t.Errorf("Benjamini-Hochberg: expecting %v instead got %v", bh, BenjaminiHochberg(p))
+			break
+		}
+	}
+}
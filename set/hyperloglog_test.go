@@ -0,0 +1,145 @@
+package set
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func intSet(from, to int) *Set {
+	A := NewSet()
+	for i := from; i < to; i++ {
+		A.Add(i)
+	}
+	return A
+}
+
+func Test_NewHyperLogLog(t *testing.T) {
+	if _, err := NewHyperLogLog(MinHyperLogLogPrecision - 1); err != ErrInvalidPrecision {
+		t.Errorf("Expecting ErrInvalidPrecision instead got %v", err)
+	}
+	if _, err := NewHyperLogLog(19); err != ErrInvalidPrecision {
+		t.Errorf("Expecting ErrInvalidPrecision instead got %v", err)
+	}
+	h, _ := NewHyperLogLog(14)
+	if c := h.Cardinality(); c != 0 {
+		t.Errorf("Expecting an empty sketch to estimate 0 instead got %f", c)
+	}
+	h.Add("a", "b", "a")
+	if c := h.Cardinality(); math.Abs(c-2) > 0.01 {
+		t.Errorf("Expecting a sparse sketch to estimate 2 instead got %f", c)
+	}
+}
+
+// Test_HyperLogLogBiasAtMinPrecision averages many independent sketches of the smallest
+// precision, so that a systematic bias shows through the much larger error of any one estimate.
+func Test_HyperLogLogBiasAtMinPrecision(t *testing.T) {
+	const trials = 200
+	for _, n := range []int{1000, 50000} {
+		var sum float64
+		for i := 0; i < trials; i++ {
+			h, _ := NewHyperLogLog(MinHyperLogLogPrecision)
+			for e := i * n; e < (i+1)*n; e++ {
+				h.Add(e)
+			}
+			sum += h.Cardinality()/float64(n) - 1
+		}
+		if bias := sum / trials; math.Abs(bias) > 0.01 {
+			t.Errorf("n=%d: expecting a mean relative error within 1%% instead got %.2f%%", n, 100*bias)
+		}
+	}
+}
+
+func Test_HyperLogLogAccuracy(t *testing.T) {
+	for _, p := range []uint8{10, 14} {
+		for _, n := range []int{100, 1000, 10000, 100000} {
+			A := intSet(0, n)
+			h, err := HyperLogLogFromSet(A, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exact := A.Cardinality()
+			if rel := math.Abs(h.Cardinality()-exact) / exact; rel > 4*h.RelativeError() {
+				t.Errorf("p=%d: expecting an estimate within %f of %f instead got %f", p, 4*h.RelativeError(), exact, h.Cardinality())
+			}
+		}
+	}
+}
+
+func Test_HyperLogLogMerge(t *testing.T) {
+	A := intSet(0, 60000)
+	B := intSet(40000, 100000)
+	for _, p := range []uint8{12, 14} {
+		ha, _ := HyperLogLogFromSet(A, p)
+		hb, _ := HyperLogLogFromSet(B, p)
+		u, err := ha.Union(hb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exact := Union(A, B).Cardinality()
+		if rel := math.Abs(u.Cardinality()-exact) / exact; rel > 4*u.RelativeError() {
+			t.Errorf("p=%d: expecting a union estimate of about %f instead got %f", p, exact, u.Cardinality())
+		}
+		inter, _ := ha.IntersectionCardinality(hb)
+		exact = Intersect(A, B).Cardinality()
+		if rel := math.Abs(inter-exact) / exact; rel > 0.15 {
+			t.Errorf("p=%d: expecting an intersection estimate of about %f instead got %f", p, exact, inter)
+		}
+	}
+	small, _ := HyperLogLogFromSet(intSet(0, 10), 14)
+	big, _ := HyperLogLogFromSet(intSet(5, 50000), 14)
+	if err := small.Merge(big); err != nil {
+		t.Fatal(err)
+	}
+	if rel := math.Abs(small.Cardinality()-50000) / 50000; rel > 0.05 {
+		t.Errorf("Expecting a sparse and dense merge to estimate about 50000 instead got %f", small.Cardinality())
+	}
+	other, _ := NewHyperLogLog(10)
+	if err := small.Merge(other); err != ErrIncompatibleSketches {
+		t.Errorf("Expecting ErrIncompatibleSketches instead got %v", err)
+	}
+}
+
+func Test_HyperLogLogSerialization(t *testing.T) {
+	for _, n := range []int{50, 50000} {
+		h, _ := HyperLogLogFromSet(intSet(0, n), 12)
+		data, err := h.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g HyperLogLog
+		if err := g.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if g.Cardinality() != h.Cardinality() {
+			t.Errorf("Expecting the decoded sketch to estimate %f instead got %f", h.Cardinality(), g.Cardinality())
+		}
+		if err := g.UnmarshalBinary(data[:len(data)-1]); err != ErrInvalidEncoding {
+			t.Errorf("Expecting ErrInvalidEncoding for truncated data instead got %v", err)
+		}
+	}
+}
+
+func Test_HyperLogLogInvalidRegisters(t *testing.T) {
+	dense, _ := HyperLogLogFromSet(intSet(0, 50000), 12)
+	sparse, _ := HyperLogLogFromSet(intSet(0, 50), 12)
+	corrupt := func(h *HyperLogLog, fn func(data []byte)) []byte {
+		data, _ := h.MarshalBinary()
+		fn(data)
+		return data
+	}
+	for name, data := range map[string][]byte{
+		"dense register too large":  corrupt(dense, func(d []byte) { d[3] = 200 }),
+		"sparse index too large":    corrupt(sparse, func(d []byte) { binary.LittleEndian.PutUint32(d[7:], 1<<25) }),
+		"sparse register of zero":   corrupt(sparse, func(d []byte) { d[11] = 0 }),
+		"sparse register too large": corrupt(sparse, func(d []byte) { d[11] = 41 }),
+		"sparse index repeated": corrupt(sparse, func(d []byte) {
+			copy(d[12:16], d[7:11])
+		}),
+	} {
+		var g HyperLogLog
+		if err := g.UnmarshalBinary(data); err != ErrInvalidEncoding {
+			t.Errorf("Expecting ErrInvalidEncoding for a %s instead got %v", name, err)
+		}
+	}
+}
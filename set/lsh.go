@@ -0,0 +1,209 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidThreshold is returned when a similarity threshold is outside the range a function accepts.
+var ErrInvalidThreshold = errors.New("set: similarity threshold out of range")
+
+// LSHIndex is a locality-sensitive hashing index over MinHash signatures. Each signature is cut
+// into b bands of r rows, and two sets become candidates when any band is identical, which for
+// sets with a Jaccard index of s happens with probability 1 - (1 - sʳ)ᵇ. That S-shaped curve
+// rises sharply around (1/b)^(1/r), so sets above the threshold are very likely found and sets
+// well below it are very likely not.
+type LSHIndex struct {
+	hasher    *MinHasher
+	bands     int
+	rows      int
+	threshold float64
+	tables    []map[uint64][]string
+	sets      map[string]*Set
+	sigs      map[string]Signature
+}
+
+// NewLSHIndex returns an index for finding sets with a Jaccard index of at least threshold,
+// using signatures of length k. The bands and rows are chosen, with b·r ≤ k, to minimise the
+// sum of the probability of false positives below the threshold and false negatives above it.
+// ErrInvalidThreshold is returned unless 0 < threshold < 1, and ErrInvalidSize if k is not positive.
+func NewLSHIndex(threshold float64, k int, seed uint64) (*LSHIndex, error) {
+	if !(threshold > 0 && threshold < 1) {
+		return nil, ErrInvalidThreshold
+	}
+	if k < 1 {
+		return nil, ErrInvalidSize
+	}
+	bands, rows := lshParameters(threshold, k)
+	I, err := NewLSHIndexBands(bands, rows, seed)
+	if err != nil {
+		return nil, err
+	}
+	I.threshold = threshold
+	return I, nil
+}
+
+// NewLSHIndexBands returns an index with the given number of bands of rows. Its threshold is
+// the approximate Jaccard index at which sets become candidates.
+// ErrInvalidSize is returned if bands or rows is not positive.
+//
+// t ≈ (1/b)^(1/r)
+func NewLSHIndexBands(bands, rows int, seed uint64) (*LSHIndex, error) {
+	if bands < 1 || rows < 1 {
+		return nil, ErrInvalidSize
+	}
+	hasher, err := NewMinHasher(bands*rows, seed)
+	if err != nil {
+		return nil, err
+	}
+	I := &LSHIndex{
+		hasher:    hasher,
+		bands:     bands,
+		rows:      rows,
+		threshold: math.Pow(1/float64(bands), 1/float64(rows)),
+		tables:    make([]map[uint64][]string, bands),
+		sets:      make(map[string]*Set),
+		sigs:      make(map[string]Signature),
+	}
+	for i := range I.tables {
+		I.tables[i] = make(map[uint64][]string)
+	}
+	return I, nil
+}
+
+// lshParameters returns the bands and rows that minimise the false-positive and false-negative
+// areas under the candidate probability curve either side of threshold.
+func lshParameters(threshold float64, k int) (bands, rows int) {
+	best := math.Inf(1)
+	for b := 1; b <= k; b++ {
+		for r := 1; b*r <= k; r++ {
+			fp := lshIntegrate(0, threshold, func(s float64) float64 {
+				return lshCandidateProbability(s, b, r)
+			})
+			fn := lshIntegrate(threshold, 1, func(s float64) float64 {
+				return 1 - lshCandidateProbability(s, b, r)
+			})
+			if err := fp + fn; err < best {
+				best, bands, rows = err, b, r
+			}
+		}
+	}
+	return
+}
+
+func lshCandidateProbability(s float64, b, r int) float64 {
+	return 1 - math.Pow(1-math.Pow(s, float64(r)), float64(b))
+}
+
+// lshIntegrate integrates f over [a,b] with the midpoint rule.
+func lshIntegrate(a, b float64, f func(float64) float64) (sum float64) {
+	const steps = 100
+	w := (b - a) / steps
+	for i := 0; i < steps; i++ {
+		sum += f(a+(float64(i)+0.5)*w) * w
+	}
+	return
+}
+
+// Bands returns the number of bands of I.
+func (I *LSHIndex) Bands() int {
+	return I.bands
+}
+
+// Rows returns the number of rows in each band of I.
+func (I *LSHIndex) Rows() int {
+	return I.rows
+}
+
+// Threshold returns the Jaccard index I is tuned for.
+func (I *LSHIndex) Threshold() float64 {
+	return I.threshold
+}
+
+// Len returns the number of sets stored in I.
+func (I *LSHIndex) Len() int {
+	return len(I.sets)
+}
+
+func (I *LSHIndex) bandKeys(s Signature) []uint64 {
+	keys := make([]uint64, I.bands)
+	for b := range keys {
+		h := uint64(fnvOffset64)
+		for _, v := range s.values[b*I.rows : (b+1)*I.rows] {
+			h = fnvUint64(h, v)
+		}
+		keys[b] = h
+	}
+	return keys
+}
+
+// Insert stores a copy of A under name, replacing any set already stored under it, so later
+// changes to A do not reach the index.
+func (I *LSHIndex) Insert(name string, A Interface) {
+	I.Remove(name)
+	C := NewSet()
+	addAll(C, A)
+	s := I.hasher.Signature(C)
+	for b, key := range I.bandKeys(s) {
+		I.tables[b][key] = append(I.tables[b][key], name)
+	}
+	I.sets[name] = C
+	I.sigs[name] = s
+}
+
+// Remove deletes the set stored under name, returning false if there was none.
+func (I *LSHIndex) Remove(name string) bool {
+	s, ok := I.sigs[name]
+	if !ok {
+		return false
+	}
+	for b, key := range I.bandKeys(s) {
+		bucket := I.tables[b][key]
+		for i, n := range bucket {
+			if n == name {
+				bucket = append(bucket[:i], bucket[i+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(I.tables[b], key)
+		} else {
+			I.tables[b][key] = bucket
+		}
+	}
+	delete(I.sets, name)
+	delete(I.sigs, name)
+	return true
+}
+
+// Query returns, in sorted order, the names of the stored sets that share a band with A and so
+// probably have a Jaccard index with A of at least the threshold of I.
+func (I *LSHIndex) Query(A Interface) []string {
+	seen := make(map[string]nothing)
+	names := make([]string, 0)
+	for b, key := range I.bandKeys(I.hasher.Signature(A)) {
+		for _, n := range I.tables[b][key] {
+			if _, ok := seen[n]; !ok {
+				seen[n] = nothing{}
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// QueryVerified returns, in sorted order, the names of the candidates from Query whose exact
+// JaccardSimilarity with A is at least the threshold of I. It has no false positives, but can
+// still miss sets that Query missed.
+func (I *LSHIndex) QueryVerified(A Interface) []string {
+	candidates := I.Query(A)
+	names := candidates[:0]
+	for _, n := range candidates {
+		if JaccardSimilarity(A, I.sets[n]) >= I.threshold {
+			names = append(names, n)
+		}
+	}
+	return names
+}
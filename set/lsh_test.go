@@ -0,0 +1,110 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func Test_NewLSHIndex(t *testing.T) {
+	if _, err := NewLSHIndex(1, 128, 0); err != ErrInvalidThreshold {
+		t.Errorf("Expecting ErrInvalidThreshold instead got %v", err)
+	}
+	if _, err := NewLSHIndex(0.5, 0, 0); err != ErrInvalidSize {
+		t.Errorf("Expecting ErrInvalidSize instead got %v", err)
+	}
+	for _, br := range [][2]int{{0, 4}, {16, 0}} {
+		if _, err := NewLSHIndexBands(br[0], br[1], 0); err != ErrInvalidSize {
+			t.Errorf("Expecting ErrInvalidSize for %d×%d instead got %v", br[0], br[1], err)
+		}
+	}
+	for _, threshold := range []float64{0.3, 0.5, 0.8} {
+		I, err := NewLSHIndex(threshold, 128, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if I.Bands()*I.Rows() > 128 {
+			t.Errorf("Expecting at most 128 hashes instead got %d×%d", I.Bands(), I.Rows())
+		}
+		curve := math.Pow(1/float64(I.Bands()), 1/float64(I.Rows()))
+		if math.Abs(curve-threshold) > 0.1 {
+			t.Errorf("Expecting the S-curve of %d×%d to rise near %f instead of %f", I.Bands(), I.Rows(), threshold, curve)
+		}
+	}
+}
+
+func Test_LSHIndexRecallPrecision(t *testing.T) {
+	const threshold = 0.5
+	I, err := NewLSHIndex(threshold, 128, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Q := intSet(0, 200)
+	stored := make(map[string]*Set)
+	for s := 0; s <= 200; s += 2 {
+		S := intSet(0, s)
+		for i := 0; i < 200-s; i++ {
+			S.Add(fmt.Sprintf("fresh-%d-%d", s, i))
+		}
+		name := fmt.Sprintf("shared-%d", s)
+		stored[name] = S
+		I.Insert(name, S)
+	}
+	relevant := 0
+	for _, S := range stored {
+		if JaccardSimilarity(Q, S) >= threshold {
+			relevant++
+		}
+	}
+	found := 0
+	falsePositives := 0
+	for _, n := range I.Query(Q) {
+		if JaccardSimilarity(Q, stored[n]) >= threshold {
+			found++
+		} else if JaccardSimilarity(Q, stored[n]) < threshold-0.2 {
+			falsePositives++
+		}
+	}
+	if recall := float64(found) / float64(relevant); recall < 0.9 {
+		t.Errorf("Expecting a recall of at least 0.9 instead got %f", recall)
+	}
+	if falsePositives > 2 {
+		t.Errorf("Expecting few candidates far below the threshold instead got %d", falsePositives)
+	}
+	verified := I.QueryVerified(Q)
+	for _, n := range verified {
+		if JaccardSimilarity(Q, stored[n]) < threshold {
+			t.Errorf("Expecting verified results to have no false positives, got %s", n)
+		}
+	}
+	if len(verified) != found {
+		t.Errorf("Expecting verification to keep all %d true candidates instead kept %d", found, len(verified))
+	}
+}
+
+func Test_LSHIndexRemove(t *testing.T) {
+	I, _ := NewLSHIndexBands(16, 4, 1)
+	A := NewSet("a", "b", "c", "d")
+	I.Insert("a", A)
+	I.Insert("b", NewSet("w", "x", "y", "z"))
+	if names := I.Query(A); len(names) != 1 || names[0] != "a" {
+		t.Errorf("Expecting to find only a instead got %v", names)
+	}
+	if !I.Remove("a") || I.Remove("a") {
+		t.Error("Expecting a to be removed exactly once")
+	}
+	if names := I.Query(A); len(names) != 0 || I.Len() != 1 {
+		t.Errorf("Expecting a to be gone instead got %v", names)
+	}
+}
+
+func Test_LSHIndexCopiesSets(t *testing.T) {
+	I, _ := NewLSHIndexBands(16, 4, 1)
+	A := NewSet("a", "b", "c", "d")
+	I.Insert("a", A)
+	A.Remove("a", "b", "c")
+	A.Add("w", "x", "y")
+	if names := I.QueryVerified(NewSet("a", "b", "c", "d")); len(names) != 1 || names[0] != "a" {
+		t.Errorf("Expecting the stored copy of a to be found instead got %v", names)
+	}
+}
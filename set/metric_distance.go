@@ -0,0 +1,423 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrNotNumeric is returned when a distance without an element metric is asked of sets with an element that is not a number.
+var ErrNotNumeric = errors.New("set: elements must be numbers when no distance is given")
+
+// ElementDistance is a metric on elements, such as the absolute difference of numbers or the
+// edit distance of strings.
+type ElementDistance func(a, b interface{}) float64
+
+// The distances below lift an element metric d to sets. When d is nil every element must be a
+// number, of any of Go's integer or floating-point types, and d is the absolute difference,
+// which lets the distance be computed from the sorted elements in O(n log n) rather than by
+// comparing every pair. The distance between a set and the empty set is +Inf, and between two
+// empty sets 0.
+
+// HausdorffDistance returns the largest distance from an element of either set to the nearest
+// element of the other: every element of A is within it of an element of B, and vice versa.
+// ErrNotNumeric is returned if d is nil and an element is not a number.
+//
+// dH(A,B) = max(sup_{a∈A} inf_{b∈B} d(a,b), sup_{b∈B} inf_{a∈A} d(a,b))
+func HausdorffDistance(A, B Interface, d ElementDistance) (float64, error) {
+	ab, ba, err := nearestDistances(A, B, d)
+	if err != nil {
+		return 0, err
+	}
+	if e, ok := emptyDistance(A, B); ok {
+		return e, nil
+	}
+	h := 0.0
+	for _, v := range append(ab, ba...) {
+		h = math.Max(h, v)
+	}
+	return h, nil
+}
+
+// AverageMinimumDistance returns the mean, over the elements of both sets, of the distance from
+// each to the nearest element of the other set. Unlike HausdorffDistance it is not dominated by
+// a single outlier.
+// ErrNotNumeric is returned if d is nil and an element is not a number.
+//
+// AMD(A,B) = (Σ_{a∈A} d(a,B) + Σ_{b∈B} d(b,A)) / (|A| + |B|)
+func AverageMinimumDistance(A, B Interface, d ElementDistance) (float64, error) {
+	ab, ba, err := nearestDistances(A, B, d)
+	if err != nil {
+		return 0, err
+	}
+	if e, ok := emptyDistance(A, B); ok {
+		return e, nil
+	}
+	sum := 0.0
+	for _, v := range append(ab, ba...) {
+		sum += v
+	}
+	return sum / float64(len(ab)+len(ba)), nil
+}
+
+// EarthMoversDistance returns the least cost of moving a unit of mass spread evenly over the
+// elements of A onto the elements of B, spread evenly over them, where moving mass m from a to b
+// costs m·d(a,b). It is the Wasserstein-1 distance between the uniform distributions on A & B.
+// For numbers it is the area between the two distribution functions. With d and
+// |A| = |B| it is the cost of the best matching divided by |A|, found by the Hungarian algorithm
+// in O(|A|³). Otherwise it is solved as a transportation problem by successive shortest paths,
+// each found in O((|A|+|B|)²); there are usually about |A|+|B| of them, and at most |A|·|B|.
+// ErrNotNumeric is returned if d is nil and an element is not a number.
+//
+// EMD(A,B) = min_{f≥0} Σ fᵢⱼ·d(aᵢ,bⱼ), Σⱼ fᵢⱼ = 1/|A|, Σᵢ fᵢⱼ = 1/|B|
+func EarthMoversDistance(A, B Interface, d ElementDistance) (float64, error) {
+	if d == nil {
+		a, b, err := sortedNumbers(A, B)
+		if err != nil {
+			return 0, err
+		}
+		if e, ok := emptyDistance(A, B); ok {
+			return e, nil
+		}
+		return numericEMD(a, b), nil
+	}
+	if e, ok := emptyDistance(A, B); ok {
+		return e, nil
+	}
+	return transport(members(A), members(B), d), nil
+}
+
+// emptyDistance returns the distance between A & B if either is empty.
+func emptyDistance(A, B Interface) (float64, bool) {
+	switch {
+	case A.Len() == 0 && B.Len() == 0:
+		return 0, true
+	case A.Len() == 0 || B.Len() == 0:
+		return math.Inf(1), true
+	}
+	return 0, false
+}
+
+// members returns the elements of A in no particular order.
+func members(A Interface) []interface{} {
+	els := make([]interface{}, 0, A.Len())
+	A.Iterate(func(e interface{}) bool {
+		els = append(els, e)
+		return true
+	})
+	return els
+}
+
+// nearestDistances returns the distance from each element of A to the nearest element of B,
+// and from each element of B to the nearest element of A.
+func nearestDistances(A, B Interface, d ElementDistance) (ab, ba []float64, err error) {
+	if d == nil {
+		a, b, err := sortedNumbers(A, B)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nearestSorted(a, b), nearestSorted(b, a), nil
+	}
+	a, b := members(A), members(B)
+	ab = make([]float64, len(a))
+	ba = make([]float64, len(b))
+	for i := range ab {
+		ab[i] = math.Inf(1)
+	}
+	for j := range ba {
+		ba[j] = math.Inf(1)
+	}
+	for i, x := range a {
+		for j, y := range b {
+			v := d(x, y)
+			ab[i] = math.Min(ab[i], v)
+			ba[j] = math.Min(ba[j], v)
+		}
+	}
+	return ab, ba, nil
+}
+
+// nearestSorted returns the distance from each of a to the nearest of the sorted b.
+func nearestSorted(a, b []float64) []float64 {
+	near := make([]float64, len(a))
+	for i, x := range a {
+		near[i] = math.Inf(1)
+		j := sort.SearchFloat64s(b, x)
+		if j < len(b) {
+			near[i] = b[j] - x
+		}
+		if j > 0 {
+			near[i] = math.Min(near[i], x-b[j-1])
+		}
+	}
+	return near
+}
+
+// toFloat returns e as a float64 if it is a number.
+func toFloat(e interface{}) (float64, bool) {
+	switch v := e.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// sortedNumbers returns the elements of A & B as sorted float64s.
+// ErrNotNumeric is returned if an element is not a number.
+func sortedNumbers(A, B Interface) (a, b []float64, err error) {
+	if a, err = sortedFloats(A); err != nil {
+		return nil, nil, err
+	}
+	if b, err = sortedFloats(B); err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+// sortedFloats returns the elements of A as sorted float64s.
+func sortedFloats(A Interface) ([]float64, error) {
+	numbers := make([]float64, 0, A.Len())
+	var err error
+	A.Iterate(func(e interface{}) bool {
+		f, ok := toFloat(e)
+		if !ok {
+			err = ErrNotNumeric
+			return false
+		}
+		numbers = append(numbers, f)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Float64s(numbers)
+	return numbers, nil
+}
+
+// numericEMD returns the area between the distribution functions of the sorted a & b.
+//
+// EMD = ∫ |F_A(x) − F_B(x)| dx
+func numericEMD(a, b []float64) float64 {
+	var emd, fa, fb float64
+	i, j := 0, 0
+	prev := math.Min(a[0], b[0])
+	for i < len(a) || j < len(b) {
+		var x float64
+		if j == len(b) || i < len(a) && a[i] <= b[j] {
+			x = a[i]
+		} else {
+			x = b[j]
+		}
+		emd += math.Abs(fa-fb) * (x - prev)
+		for i < len(a) && a[i] == x {
+			fa += 1 / float64(len(a))
+			i++
+		}
+		for j < len(b) && b[j] == x {
+			fb += 1 / float64(len(b))
+			j++
+		}
+		prev = x
+	}
+	return emd
+}
+
+// transport solves the transportation problem from a to b by successive shortest paths. Every
+// element of a supplies |b| units and every element of b demands |a|, so that flows are integers.
+func transport(a, b []interface{}, d ElementDistance) float64 {
+	n, m := len(a), len(b)
+	cost := make([][]float64, n)
+	flow := make([][]int, n)
+	for i := range cost {
+		cost[i] = make([]float64, m)
+		flow[i] = make([]int, m)
+		for j := range cost[i] {
+			cost[i][j] = d(a[i], b[j])
+		}
+	}
+	if n == m {
+		// With equal masses on both sides some optimal flow is a permutation, by the
+		// Birkhoff–von Neumann theorem, so the least-cost assignment solves it faster.
+		return assignment(cost) / float64(n)
+	}
+	supply := make([]int, n)
+	for i := range supply {
+		supply[i] = m
+	}
+	demand := make([]int, m)
+	for j := range demand {
+		demand[j] = n
+	}
+	// potA & potB keep reduced costs non-negative so that Dijkstra finds the shortest paths in
+	// the residual graph, which has negative costs on the reverse of edges carrying flow.
+	potA, potB := make([]float64, n), make([]float64, m)
+	distA, distB := make([]float64, n), make([]float64, m)
+	fromA := make([]int, m) // the a from which each b was reached
+	fromB := make([]int, n) // the b from which each a was reached, or -1 from the source
+	doneA, doneB := make([]bool, n), make([]bool, m)
+	total := 0.0
+	for remaining := n * m; remaining > 0; {
+		for i := range distA {
+			distA[i], doneA[i], fromB[i] = math.Inf(1), false, -1
+			if supply[i] > 0 {
+				distA[i] = 0
+			}
+		}
+		for j := range distB {
+			distB[j], doneB[j] = math.Inf(1), false
+		}
+		// Settle nodes until the nearest b with demand left, the end of the shortest path.
+		end := -1
+		for end < 0 {
+			// Settle the closest unsettled node, on either side.
+			bi, bj, best := -1, -1, math.Inf(1)
+			for i, v := range distA {
+				if !doneA[i] && v < best {
+					bi, bj, best = i, -1, v
+				}
+			}
+			for j, v := range distB {
+				if !doneB[j] && v < best {
+					bi, bj, best = -1, j, v
+				}
+			}
+			if bi >= 0 {
+				doneA[bi] = true
+				for j := range distB {
+					if v := best + math.Max(0, cost[bi][j]+potA[bi]-potB[j]); !doneB[j] && v < distB[j] {
+						distB[j], fromA[j] = v, bi
+					}
+				}
+				continue
+			}
+			doneB[bj] = true
+			if demand[bj] > 0 {
+				end = bj
+				continue
+			}
+			for i := range distA {
+				if flow[i][bj] > 0 {
+					if v := best + math.Max(0, potB[bj]-cost[i][bj]-potA[i]); !doneA[i] && v < distA[i] {
+						distA[i], fromB[i] = v, bj
+					}
+				}
+			}
+		}
+		// Augment along the shortest path.
+		amount := demand[end]
+		for j := end; ; {
+			i := fromA[j]
+			if fromB[i] < 0 {
+				if supply[i] < amount {
+					amount = supply[i]
+				}
+				break
+			}
+			if f := flow[i][fromB[i]]; f < amount {
+				amount = f
+			}
+			j = fromB[i]
+		}
+		for j := end; ; {
+			i := fromA[j]
+			flow[i][j] += amount
+			total += float64(amount) * cost[i][j]
+			if fromB[i] < 0 {
+				supply[i] -= amount
+				break
+			}
+			flow[i][fromB[i]] -= amount
+			total -= float64(amount) * cost[i][fromB[i]]
+			j = fromB[i]
+		}
+		demand[end] -= amount
+		remaining -= amount
+		// Nodes left unsettled are at least as far as the end, so capping every distance there
+		// keeps the reduced costs non-negative.
+		limit := distB[end]
+		for i := range potA {
+			potA[i] += math.Min(distA[i], limit)
+		}
+		for j := range potB {
+			potB[j] += math.Min(distB[j], limit)
+		}
+	}
+	return total / float64(n*m)
+}
+
+// assignment returns the least total cost of matching each row of the square matrix cost with a
+// distinct column, by the Hungarian algorithm in O(n³).
+func assignment(cost [][]float64) float64 {
+	n := len(cost)
+	// Rows & columns are numbered from 1, and column 0 is a sentinel matched to the row being
+	// added. u & v are dual potentials with u[i]+v[j] ≤ cost[i-1][j-1].
+	u, v := make([]float64, n+1), make([]float64, n+1)
+	match := make([]int, n+1) // the row matched to each column, or 0
+	way := make([]int, n+1)   // the previous column on the alternating path to each column
+	minv := make([]float64, n+1)
+	used := make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		match[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j], used[j] = math.Inf(1), false
+		}
+		for match[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := match[j0], math.Inf(1), 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				if c := cost[i0-1][j-1] - u[i0] - v[j]; c < minv[j] {
+					minv[j], way[j] = c, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		// Flip the alternating path back to the sentinel.
+		for j0 != 0 {
+			j1 := way[j0]
+			match[j0] = match[j1]
+			j0 = j1
+		}
+	}
+	total := 0.0
+	for j := 1; j <= n; j++ {
+		total += cost[match[j]-1][j-1]
+	}
+	return total
+}
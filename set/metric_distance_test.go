@@ -0,0 +1,159 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func absDistance(a, b interface{}) float64 {
+	x, _ := toFloat(a)
+	y, _ := toFloat(b)
+	return math.Abs(x - y)
+}
+
+type point struct{ x, y float64 }
+
+func euclidean(a, b interface{}) float64 {
+	p, q := a.(point), b.(point)
+	return math.Hypot(p.x-q.x, p.y-q.y)
+}
+
+func Test_MetricDistances(t *testing.T) {
+	A, B := NewSet(0, 1, 2), NewSet(1.5, uint8(10))
+	for _, d := range []ElementDistance{nil, absDistance} {
+		for _, c := range []struct {
+			name string
+			f    func(A, B Interface, d ElementDistance) (float64, error)
+			want float64
+		}{
+			{"HausdorffDistance", HausdorffDistance, 8},
+			{"AverageMinimumDistance", AverageMinimumDistance, 2.2},
+			{"EarthMoversDistance", EarthMoversDistance, 4.75},
+		} {
+			for _, sets := range [][2]*Set{{A, B}, {B, A}} {
+				got, err := c.f(sets[0], sets[1], d)
+				if err != nil || math.Abs(got-c.want) > 1e-12 {
+					t.Errorf("Expecting %s(%v, %v) = %v instead got %v, %v", c.name, sets[0], sets[1], c.want, got, err)
+				}
+			}
+			if got, _ := c.f(A, A, d); got != 0 {
+				t.Errorf("Expecting %s of a set with itself to be 0 instead got %v", c.name, got)
+			}
+			if got, _ := c.f(A, NewSet(), d); !math.IsInf(got, 1) {
+				t.Errorf("Expecting %s with the empty set to be +Inf instead got %v", c.name, got)
+			}
+			if got, _ := c.f(NewSet(), NewSet(), d); got != 0 {
+				t.Errorf("Expecting %s of two empty sets to be 0 instead got %v", c.name, got)
+			}
+			if _, err := c.f(A, NewSet("a"), nil); err != ErrNotNumeric {
+				t.Errorf("Expecting ErrNotNumeric from %s instead got %v", c.name, err)
+			}
+		}
+	}
+}
+
+func Test_EarthMoversDistancePoints(t *testing.T) {
+	// Each point of A moves all its mass a distance of 1 to B, or half of it 2 to C.
+	A := NewSet(point{0, 0}, point{0, 1})
+	B := NewSet(point{1, 0}, point{1, 1})
+	if got, _ := EarthMoversDistance(A, B, euclidean); math.Abs(got-1) > 1e-12 {
+		t.Errorf("Expecting 1 instead got %v", got)
+	}
+	C := NewSet(point{0, 0}, point{0, 1}, point{2, 0}, point{2, 1})
+	if got, _ := EarthMoversDistance(A, C, euclidean); math.Abs(got-1) > 1e-12 {
+		t.Errorf("Expecting 1 instead got %v", got)
+	}
+	if got, _ := HausdorffDistance(A, C, euclidean); got != 2 {
+		t.Errorf("Expecting 2 instead got %v", got)
+	}
+}
+
+// minAssignment returns the least mean cost of matching a & b, of equal length, one to one.
+func minAssignment(a, b []interface{}, d ElementDistance) float64 {
+	best := math.Inf(1)
+	var permute func(k int, cost float64)
+	permute = func(k int, cost float64) {
+		if k == len(b) {
+			best = math.Min(best, cost)
+			return
+		}
+		for i := k; i < len(b); i++ {
+			b[k], b[i] = b[i], b[k]
+			permute(k+1, cost+d(a[k], b[k]))
+			b[k], b[i] = b[i], b[k]
+		}
+	}
+	permute(0, 0)
+	return best / float64(len(a))
+}
+
+func Test_EarthMoversDistanceRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(48))
+	for trial := 0; trial < 50; trial++ {
+		A, B := NewSet(), NewSet()
+		for i := 1 + r.Intn(12); i > 0; i-- {
+			A.Add(r.Intn(100))
+		}
+		for i := 1 + r.Intn(12); i > 0; i-- {
+			B.Add(r.Float64() * 100)
+		}
+		// The general solver must agree with the distribution functions on the line.
+		want, _ := EarthMoversDistance(A, B, nil)
+		if got, _ := EarthMoversDistance(A, B, absDistance); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expecting %v for %v and %v instead got %v", want, A, B, got)
+		}
+		for _, f := range []func(A, B Interface, d ElementDistance) (float64, error){HausdorffDistance, AverageMinimumDistance} {
+			want, _ := f(A, B, nil)
+			if got, _ := f(A, B, absDistance); math.Abs(got-want) > 1e-9 {
+				t.Errorf("Expecting %v for %v and %v instead got %v", want, A, B, got)
+			}
+		}
+		// With as many points on each side, the distance is that of the best matching.
+		P, Q := NewSet(), NewSet()
+		for n := 1 + r.Intn(6); P.Len() < n; {
+			P.Add(point{r.Float64(), r.Float64()})
+			Q.Add(point{r.Float64(), r.Float64()})
+		}
+		want = minAssignment(members(P), members(Q), euclidean)
+		if got, _ := EarthMoversDistance(P, Q, euclidean); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expecting %v for %v and %v instead got %v", want, P, Q, got)
+		}
+	}
+}
+
+func Test_EarthMoversDistanceLarge(t *testing.T) {
+	r := rand.New(rand.NewSource(49))
+	for _, size := range [][2]int{{200, 200}, {200, 130}, {90, 170}} {
+		A, B := NewSet(), NewSet()
+		for A.Len() < size[0] {
+			A.Add(r.Float64() * 100)
+		}
+		for B.Len() < size[1] {
+			B.Add(r.Float64()*100 + 10)
+		}
+		want, _ := EarthMoversDistance(A, B, nil)
+		if got, _ := EarthMoversDistance(A, B, absDistance); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%d×%d: expecting %v instead got %v", size[0], size[1], want, got)
+		}
+	}
+}
+
+func BenchmarkEarthMoversDistance(b *testing.B) {
+	r := rand.New(rand.NewSource(48))
+	for _, size := range [][2]int{{300, 300}, {300, 200}} {
+		A, B := NewSet(), NewSet()
+		for A.Len() < size[0] {
+			A.Add(point{r.Float64(), r.Float64()})
+		}
+		for B.Len() < size[1] {
+			B.Add(point{r.Float64(), r.Float64()})
+		}
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				EarthMoversDistance(A, B, euclidean)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// ErrInvalidBits is returned when a b-bit signature is requested with b outside of [1,64].
+var ErrInvalidBits = errors.New("set: bits must be in [1,64]")
+
+// emptyBin marks a signature value with no element, which only happens for the empty set.
+const emptyBin = math.MaxUint64
+
+// MinHasher computes MinHash signatures of sets. The probability that two signatures agree at
+// any position equals the Jaccard index of the sets, so the fraction of agreeing positions of
+// signatures of length k is an unbiased estimate of JaccardSimilarity with a standard error of
+// √(J(1-J)/k). By Hoeffding's inequality the error exceeds √(ln(2/δ)/2k) with probability at
+// most δ, e.g. about ±0.085 with 99% confidence for k = 256.
+//
+// Signatures from different hashers can only be compared when the hashers were created with
+// the same k, seed and scheme.
+type MinHasher struct {
+	k     int
+	seed  uint64
+	seeds []uint64
+	oph   bool
+}
+
+// NewMinHasher returns a hasher that computes signatures of length k by taking, for each of k
+// independent hash functions, the smallest hash of any element. It costs k hashes per element.
+// ErrInvalidSize is returned if k is not positive.
+func NewMinHasher(k int, seed uint64) (*MinHasher, error) {
+	if k < 1 {
+		return nil, ErrInvalidSize
+	}
+	h := &MinHasher{k: k, seed: seed, seeds: make([]uint64, k)}
+	for i := range h.seeds {
+		h.seeds[i] = mix64(seed + uint64(i+1)*0x9e3779b97f4a7c15)
+	}
+	return h, nil
+}
+
+// NewOnePermutationMinHasher returns a hasher that computes signatures of length k with a single
+// hash per element, by splitting the hash range into k bins and keeping the smallest hash in
+// each. Bins left empty by small sets are filled by optimal densification, borrowing the value
+// of a bin chosen by a hash of the empty bin, which keeps the estimate unbiased.
+// ErrInvalidSize is returned if k is not positive.
+func NewOnePermutationMinHasher(k int, seed uint64) (*MinHasher, error) {
+	h, err := NewMinHasher(k, seed)
+	if err != nil {
+		return nil, err
+	}
+	h.oph = true
+	return h, nil
+}
+
+// Signature is the MinHash signature of a set.
+type Signature struct {
+	values   []uint64
+	seed     uint64
+	oph      bool
+	weighted bool
+}
+
+// Len returns the number of values in s.
+func (s Signature) Len() int {
+	return len(s.values)
+}
+
+// Signature returns the MinHash signature of A.
+func (h *MinHasher) Signature(A Interface) Signature {
+	s := Signature{values: make([]uint64, h.k), seed: h.seed, oph: h.oph}
+	for i := range s.values {
+		s.values[i] = emptyBin
+	}
+	if h.oph {
+		h.onePermutation(A, s.values)
+		return s
+	}
+	A.Iterate(func(e interface{}) bool {
+		x := hashElement(e, h.seed)
+		for i, seed := range h.seeds {
+			if v := mix64(x ^ seed); v < s.values[i] {
+				s.values[i] = v
+			}
+		}
+		return true
+	})
+	return s
+}
+
+func (h *MinHasher) onePermutation(A Interface, values []uint64) {
+	k := uint64(h.k)
+	A.Iterate(func(e interface{}) bool {
+		// The high word of x·k is the bin and the low word the position within it.
+		bin, v := bits.Mul64(hashElement(e, h.seed), k)
+		if v < values[bin] {
+			values[bin] = v
+		}
+		return true
+	})
+	if A.Len() == 0 {
+		return
+	}
+	filled := make([]bool, len(values))
+	for i, v := range values {
+		filled[i] = v != emptyBin
+	}
+	for i := range values {
+		if filled[i] {
+			continue
+		}
+		for attempt := uint64(1); ; attempt++ {
+			j, _ := bits.Mul64(mix64(h.seeds[i]+attempt), k)
+			if filled[j] {
+				values[i] = values[j]
+				break
+			}
+		}
+	}
+}
+
+func (s Signature) compatible(t Signature) bool {
+	return len(s.values) == len(t.values) && s.seed == t.seed && s.oph == t.oph && s.weighted == t.weighted
+}
+
+// Jaccard returns the estimated JaccardSimilarity of the sets s & t were computed from.
+// ErrIncompatibleSketches is returned if s & t were computed by different hashers, and an
+// *UndefinedSimilarityError if both sets were empty and opts choose no value for them.
+func (s Signature) Jaccard(t Signature, opts ...SimilarityOption) (float64, error) {
+	if !s.compatible(t) {
+		return 0, ErrIncompatibleSketches
+	}
+	if len(s.values) == 0 || s.values[0] == emptyBin && t.values[0] == emptyBin {
+		return checked(opts, func(o ...SimilarityOption) float64 { return jaccard(0, 0, 0, o) })
+	}
+	if s.values[0] == emptyBin || t.values[0] == emptyBin {
+		return 0, nil
+	}
+	matches := 0
+	for i, v := range s.values {
+		if v == t.values[i] {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(s.values)), nil
+}
+
+// StandardError returns the standard error of a Jaccard estimate of j from signatures of h.
+//
+// σ = √(J(1-J)/k)
+func (h *MinHasher) StandardError(j float64) float64 {
+	return math.Sqrt(j * (1 - j) / float64(h.k))
+}
+
+// BBitSignature is a MinHash signature that keeps only the lowest b bits of each value, packed
+// together, so that it uses k·b bits instead of k·64.
+type BBitSignature struct {
+	b        uint
+	k        int
+	words    []uint64
+	seed     uint64
+	oph      bool
+	weighted bool
+	empty    bool
+}
+
+// Compress returns the b-bit signature of s.
+// ErrInvalidBits is returned unless 1 ≤ b ≤ 64.
+func (s Signature) Compress(b uint) (BBitSignature, error) {
+	if b < 1 || b > 64 {
+		return BBitSignature{}, ErrInvalidBits
+	}
+	c := BBitSignature{
+		b:        b,
+		k:        len(s.values),
+		words:    make([]uint64, (uint(len(s.values))*b+63)/64),
+		seed:     s.seed,
+		oph:      s.oph,
+		weighted: s.weighted,
+		empty:    len(s.values) > 0 && s.values[0] == emptyBin,
+	}
+	mask := ^uint64(0) >> (64 - b)
+	for i, v := range s.values {
+		c.set(i, v&mask)
+	}
+	return c, nil
+}
+
+func (c BBitSignature) set(i int, v uint64) {
+	off := uint(i) * c.b
+	w, shift := off/64, off%64
+	c.words[w] |= v << shift
+	if shift+c.b > 64 {
+		c.words[w+1] |= v >> (64 - shift)
+	}
+}
+
+func (c BBitSignature) get(i int) uint64 {
+	off := uint(i) * c.b
+	w, shift := off/64, off%64
+	v := c.words[w] >> shift
+	if shift+c.b > 64 {
+		v |= c.words[w+1] << (64 - shift)
+	}
+	return v & (^uint64(0) >> (64 - c.b))
+}
+
+// Jaccard returns the estimated JaccardSimilarity of the sets s & t were computed from.
+// Unrelated values agree on their lowest b bits with probability 2⁻ᵇ, so the raw match rate P
+// is corrected for those accidental matches, which inflates the standard error of the plain
+// MinHash estimate by a factor of about 1/(1-2⁻ᵇ) and more for small b.
+// ErrIncompatibleSketches is returned if s & t were computed by different hashers or with
+// different b, and an *UndefinedSimilarityError if both sets were empty and opts choose no
+// value for them.
+//
+// Ĵ = (P - 2⁻ᵇ) / (1 - 2⁻ᵇ)
+func (c BBitSignature) Jaccard(t BBitSignature, opts ...SimilarityOption) (float64, error) {
+	if c.b != t.b || c.k != t.k || c.seed != t.seed || c.oph != t.oph || c.weighted != t.weighted {
+		return 0, ErrIncompatibleSketches
+	}
+	if c.k == 0 || c.empty && t.empty {
+		return checked(opts, func(o ...SimilarityOption) float64 { return jaccard(0, 0, 0, o) })
+	}
+	if c.empty || t.empty {
+		return 0, nil
+	}
+	matches := 0
+	for i := 0; i < c.k; i++ {
+		if c.get(i) == t.get(i) {
+			matches++
+		}
+	}
+	p := float64(matches) / float64(c.k)
+	chance := math.Ldexp(1, -int(c.b))
+	return math.Max(0, (p-chance)/(1-chance)), nil
+}
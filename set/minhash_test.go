@@ -0,0 +1,114 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+// overlappingSets returns sets of 1000 elements sharing shared elements.
+func overlappingSets(shared int) (*Set, *Set) {
+	return intSet(0, 1000), intSet(1000-shared, 2000-shared)
+}
+
+func Test_NewMinHasher(t *testing.T) {
+	for _, k := range []int{0, -1} {
+		if _, err := NewMinHasher(k, 1); err != ErrInvalidSize {
+			t.Errorf("Expecting ErrInvalidSize for k = %d instead got %v", k, err)
+		}
+		if _, err := NewOnePermutationMinHasher(k, 1); err != ErrInvalidSize {
+			t.Errorf("Expecting ErrInvalidSize for k = %d instead got %v", k, err)
+		}
+	}
+}
+
+func Test_MinHashJaccard(t *testing.T) {
+	kh, _ := NewMinHasher(256, 1)
+	oph, _ := NewOnePermutationMinHasher(256, 1)
+	hashers := map[string]*MinHasher{
+		"k hashes":        kh,
+		"one permutation": oph,
+	}
+	for name, h := range hashers {
+		for _, shared := range []int{0, 100, 500, 900, 1000} {
+			A, B := overlappingSets(shared)
+			exact := JaccardSimilarity(A, B)
+			est, err := h.Signature(A).Jaccard(h.Signature(B))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := math.Abs(est - exact); diff > 4*h.StandardError(exact)+0.01 {
+				t.Errorf("%s: expecting an estimate of about %f instead got %f", name, exact, est)
+			}
+		}
+	}
+}
+
+func Test_MinHashSmallSets(t *testing.T) {
+	h, _ := NewOnePermutationMinHasher(128, 7)
+	A := NewSet("ni", "ig", "gh", "ht")
+	B := NewSet("na", "ac", "ch", "ht")
+	sa, sb := h.Signature(A), h.Signature(B)
+	for i, v := range sa.values {
+		if v == emptyBin {
+			t.Fatalf("Expecting densification to fill every bin, bin %d is empty", i)
+		}
+	}
+	if est, _ := sa.Jaccard(h.Signature(A)); est != 1 {
+		t.Errorf("Expecting identical sets to estimate 1 instead got %f", est)
+	}
+	if est, _ := sa.Jaccard(sb); math.Abs(est-JaccardSimilarity(A, B)) > 0.15 {
+		t.Errorf("Expecting an estimate of about %f instead got %f", JaccardSimilarity(A, B), est)
+	}
+	empty := h.Signature(NewSet())
+	if est, _ := empty.Jaccard(sa); est != 0 {
+		t.Errorf("Expecting the empty set to estimate 0 instead got %f", est)
+	}
+	if est, _ := empty.Jaccard(empty); !math.IsNaN(est) {
+		t.Errorf("Expecting two empty sets to estimate NaN like JaccardSimilarity instead got %f", est)
+	}
+	g, _ := NewMinHasher(128, 7)
+	if _, err := sa.Jaccard(g.Signature(A)); err != ErrIncompatibleSketches {
+		t.Errorf("Expecting ErrIncompatibleSketches instead got %v", err)
+	}
+}
+
+func Test_BBitMinHash(t *testing.T) {
+	h, _ := NewMinHasher(1024, 3)
+	for _, b := range []uint{1, 4, 8, 13, 64} {
+		for _, shared := range []int{0, 300, 800} {
+			A, B := overlappingSets(shared)
+			exact := JaccardSimilarity(A, B)
+			ca, err := h.Signature(A).Compress(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cb, _ := h.Signature(B).Compress(b)
+			est, err := ca.Jaccard(cb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tolerance := 4*h.StandardError(exact)/(1-math.Ldexp(1, -int(b))) + 0.03
+			if b == 1 {
+				tolerance *= 2
+			}
+			if math.Abs(est-exact) > tolerance {
+				t.Errorf("b=%d: expecting an estimate of about %f instead got %f", b, exact, est)
+			}
+		}
+	}
+	s := h.Signature(intSet(0, 10))
+	for _, b := range []uint{0, 65} {
+		if _, err := s.Compress(b); err != ErrInvalidBits {
+			t.Errorf("Expecting ErrInvalidBits for b=%d instead got %v", b, err)
+		}
+	}
+	c, _ := s.Compress(13)
+	if len(c.words) != (1024*13+63)/64 {
+		t.Errorf("Expecting %d words instead got %d", (1024*13+63)/64, len(c.words))
+	}
+	for i, v := range s.values {
+		if c.get(i) != v&(1<<13-1) {
+			t.Fatalf("Expecting value %d to keep its lowest 13 bits", i)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package set
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrIncompatiblePartitions is returned when comparing partitions of different universes.
+var ErrIncompatiblePartitions = errors.New("set: partitions are of different universes")
+
+// Partition is a division of a universe U into non-empty, pairwise disjoint blocks that cover
+// it, such as the clusters of a clustering.
+type Partition struct {
+	U       *Set
+	blocks  []*Set
+	blockOf map[interface{}]int
+}
+
+// NewPartition returns the partition of U into blocks.
+// ErrNotPartition is returned if the blocks are empty, overlap or do not cover U exactly.
+func NewPartition(U *Set, blocks ...*Set) (*Partition, error) {
+	B := make([]Interface, len(blocks))
+	for i, b := range blocks {
+		B[i] = b
+	}
+	blockOf, err := blockIndex(U, B)
+	if err != nil {
+		return nil, err
+	}
+	return &Partition{U: U, blocks: blocks, blockOf: blockOf}, nil
+}
+
+// blockIndex returns the index of the block holding each element of U.
+// ErrNotPartition is returned if the blocks are empty, overlap or do not cover U exactly.
+func blockIndex(U Interface, blocks []Interface) (map[interface{}]int, error) {
+	blockOf := make(map[interface{}]int, U.Len())
+	for i, b := range blocks {
+		if b.Len() == 0 {
+			return nil, ErrNotPartition
+		}
+		ok := true
+		b.Iterate(func(e interface{}) bool {
+			if _, seen := blockOf[e]; seen || !U.Contains(e) {
+				ok = false
+				return false
+			}
+			blockOf[e] = i
+			return true
+		})
+		if !ok {
+			return nil, ErrNotPartition
+		}
+	}
+	if len(blockOf) != U.Len() {
+		return nil, ErrNotPartition
+	}
+	return blockOf, nil
+}
+
+// PartitionFromClusters returns the partition of the union of the blocks in C, a set of *Set
+// such as the clusters returned by Matrix.Hierarchical.
+// ErrNotPartition is returned if C holds anything but *Set, or its blocks are empty or overlap.
+func PartitionFromClusters(C *Set) (*Partition, error) {
+	U := NewSet()
+	blocks := make([]*Set, 0, C.Len())
+	for b := range C.E {
+		B, ok := b.(*Set)
+		if !ok {
+			return nil, ErrNotPartition
+		}
+		blocks = append(blocks, B)
+		addAll(U, B)
+	}
+	return NewPartition(U, blocks...)
+}
+
+// Blocks returns the blocks of P.
+func (P *Partition) Blocks() []*Set {
+	return P.blocks
+}
+
+// Len returns the number of blocks of P.
+func (P *Partition) Len() int {
+	return len(P.blocks)
+}
+
+// Block returns the block of P holding x, and false if x is not in U.
+func (P *Partition) Block(x interface{}) (*Set, bool) {
+	i, ok := P.blockOf[x]
+	if !ok {
+		return nil, false
+	}
+	return P.blocks[i], true
+}
+
+// Entropy returns the Shannon entropy, in nats, of the block of an element chosen uniformly at random.
+//
+// H(P) = −Σ (|Bᵢ|/n) ln(|Bᵢ|/n)
+func (P *Partition) Entropy() float64 {
+	sizes := make([]float64, len(P.blocks))
+	for i, b := range P.blocks {
+		sizes[i] = float64(b.Len())
+	}
+	return entropy(sizes, float64(P.U.Len()))
+}
+
+// contingency holds the sizes of the blocks of two partitions and of their intersections.
+type contingency struct {
+	n      float64
+	a, b   []float64
+	common map[[2]int]float64
+}
+
+func newContingency(P, Q *Partition) (*contingency, error) {
+	if !P.U.IsEqual(Q.U) {
+		return nil, ErrIncompatiblePartitions
+	}
+	c := &contingency{
+		n:      float64(P.U.Len()),
+		a:      make([]float64, len(P.blocks)),
+		b:      make([]float64, len(Q.blocks)),
+		common: make(map[[2]int]float64),
+	}
+	for i, B := range P.blocks {
+		c.a[i] = float64(B.Len())
+	}
+	for j, B := range Q.blocks {
+		c.b[j] = float64(B.Len())
+	}
+	for e, i := range P.blockOf {
+		c.common[[2]int{i, Q.blockOf[e]}]++
+	}
+	return c, nil
+}
+
+func pairs(n float64) float64 {
+	return n * (n - 1) / 2
+}
+
+// pairCounts returns the number of pairs of elements together in both partitions, together in
+// P, together in Q, and in all.
+func (c *contingency) pairCounts() (both, inP, inQ, all float64) {
+	for _, n := range c.common {
+		both += pairs(n)
+	}
+	for _, n := range c.a {
+		inP += pairs(n)
+	}
+	for _, n := range c.b {
+		inQ += pairs(n)
+	}
+	return both, inP, inQ, pairs(c.n)
+}
+
+func (c *contingency) mutualInformation() (mi float64) {
+	for ij, n := range c.common {
+		mi += n / c.n * math.Log(c.n*n/(c.a[ij[0]]*c.b[ij[1]]))
+	}
+	return
+}
+
+func entropy(sizes []float64, n float64) (h float64) {
+	for _, s := range sizes {
+		h -= s / n * math.Log(s/n)
+	}
+	return
+}
+
+// RandIndex returns the fraction of pairs of elements on which P & Q agree, either putting
+// both in one block or each in a different block. It is 1 for universes of fewer than two elements.
+// ErrIncompatiblePartitions is returned if P & Q partition different universes.
+//
+// RI = (pairs together in both + pairs apart in both) / C(n,2)
+func RandIndex(P, Q *Partition) (float64, error) {
+	c, err := newContingency(P, Q)
+	if err != nil {
+		return 0, err
+	}
+	both, inP, inQ, all := c.pairCounts()
+	if all == 0 {
+		return 1, nil
+	}
+	return (all - inP - inQ + 2*both) / all, nil
+}
+
+// AdjustedRandIndex returns the Rand index corrected for chance, so that it is 0 on average
+// for random partitions with the block sizes of P & Q, and 1 when they are equal. It is 1 when
+// the correction leaves nothing to compare, as when both put every element in its own block.
+// ErrIncompatiblePartitions is returned if P & Q partition different universes.
+//
+// ARI = (Σ C(nᵢⱼ,2) − E) / (½(Σ C(aᵢ,2) + Σ C(bⱼ,2)) − E), E = Σ C(aᵢ,2)·Σ C(bⱼ,2) / C(n,2)
+func AdjustedRandIndex(P, Q *Partition) (float64, error) {
+	c, err := newContingency(P, Q)
+	if err != nil {
+		return 0, err
+	}
+	both, inP, inQ, all := c.pairCounts()
+	if all == 0 {
+		return 1, nil
+	}
+	expected := inP * inQ / all
+	max := (inP + inQ) / 2
+	if max == expected {
+		return 1, nil
+	}
+	return (both - expected) / (max - expected), nil
+}
+
+// NormalizedMutualInformation returns the mutual information of the blocks of P & Q divided by
+// the mean of their entropies, in [0,1]. It is 1 when both entropies are 0, as when both
+// partitions have a single block.
+// ErrIncompatiblePartitions is returned if P & Q partition different universes.
+//
+// NMI = I(P;Q) / ½(H(P) + H(Q))
+func NormalizedMutualInformation(P, Q *Partition) (float64, error) {
+	c, err := newContingency(P, Q)
+	if err != nil {
+		return 0, err
+	}
+	h := (entropy(c.a, c.n) + entropy(c.b, c.n)) / 2
+	if h == 0 {
+		return 1, nil
+	}
+	return math.Max(0, math.Min(1, c.mutualInformation()/h)), nil
+}
+
+// VariationOfInformation returns the information, in nats, lost and gained in moving from P to
+// Q. It is a metric on the partitions of a universe: 0 exactly when P & Q are equal.
+// ErrIncompatiblePartitions is returned if P & Q partition different universes.
+//
+// VI = H(P) + H(Q) − 2 I(P;Q)
+func VariationOfInformation(P, Q *Partition) (float64, error) {
+	c, err := newContingency(P, Q)
+	if err != nil {
+		return 0, err
+	}
+	vi := entropy(c.a, c.n) + entropy(c.b, c.n) - 2*c.mutualInformation()
+	return math.Max(0, vi), nil
+}
+
+// FowlkesMallows returns the geometric mean of the precision and recall of the pairs of
+// elements put together by Q against those put together by P. Like scikit-learn, it is 0 when
+// either partition puts no two elements together.
+// ErrIncompatiblePartitions is returned if P & Q partition different universes.
+//
+// FM = Σ C(nᵢⱼ,2) / √(Σ C(aᵢ,2) · Σ C(bⱼ,2))
+func FowlkesMallows(P, Q *Partition) (float64, error) {
+	c, err := newContingency(P, Q)
+	if err != nil {
+		return 0, err
+	}
+	both, inP, inQ, _ := c.pairCounts()
+	if inP == 0 || inQ == 0 {
+		return 0, nil
+	}
+	return both / math.Sqrt(inP*inQ), nil
+}
@@ -0,0 +1,85 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+// labelled returns the partition of {0, …, n-1} putting i in the block labels[i].
+func labelled(labels ...int) *Partition {
+	blocks := make(map[int]*Set)
+	var order []*Set
+	for i, l := range labels {
+		if _, ok := blocks[l]; !ok {
+			blocks[l] = NewSet()
+			order = append(order, blocks[l])
+		}
+		blocks[l].Add(i)
+	}
+	P, err := NewPartition(intSet(0, len(labels)), order...)
+	if err != nil {
+		panic(err)
+	}
+	return P
+}
+
+func Test_NewPartition(t *testing.T) {
+	U := NewSet(1, 2, 3, 4)
+	for _, blocks := range [][]*Set{
+		{NewSet(1, 2), NewSet(2, 3, 4)},
+		{NewSet(1, 2), NewSet(3)},
+		{NewSet(1, 2), NewSet(3, 4, 5)},
+		{NewSet(1, 2, 3, 4), NewSet()},
+	} {
+		if _, err := NewPartition(U, blocks...); err != ErrNotPartition {
+			t.Errorf("Expecting ErrNotPartition for %v instead got %v", blocks, err)
+		}
+	}
+	P, err := PartitionFromClusters(NewSet(NewSet(1, 2), NewSet(3), NewSet(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !P.U.IsEqual(U) || P.Len() != 3 {
+		t.Errorf("Expecting 3 blocks of %v instead got %d of %v", U, P.Len(), P.U)
+	}
+	if b, ok := P.Block(2); !ok || !b.IsEqual(NewSet(1, 2)) {
+		t.Errorf("Expecting 2 to be in {1,2} instead got %v", b)
+	}
+	if _, err := PartitionFromClusters(NewSet(1, NewSet(2))); err != ErrNotPartition {
+		t.Errorf("Expecting ErrNotPartition instead got %v", err)
+	}
+}
+
+func Test_PartitionComparison(t *testing.T) {
+	// Reference values were computed independently from the contingency tables of the labellings.
+	tests := []struct {
+		P, Q                    *Partition
+		ri, ari, nmi, vi, fm, h float64
+	}{
+		{labelled(0, 0, 0, 1, 1, 1), labelled(0, 0, 1, 1, 2, 2),
+			0.6666666666666666, 0.24242424242424246, 0.5158037429793888, 0.8675632284814613, 0.47140452079103173, 0.6931471805599453},
+		{labelled(0, 0, 1, 1), labelled(0, 0, 1, 2),
+			0.8333333333333334, 0.5714285714285715, 0.8, 0.3465735902799725, 0.7071067811865475, 0.6931471805599453},
+		{labelled(0, 0, 1, 1), labelled(5, 5, 3, 3), 1, 1, 1, 0, 1, 0.6931471805599453},
+		{labelled(0, 1, 2), labelled(0, 1, 2), 1, 1, 1, 0, 0, 1.0986122886681096},
+		{labelled(0, 0, 0), labelled(0, 0, 0), 1, 1, 1, 0, 1, 0},
+	}
+	for i, tt := range tests {
+		ri, _ := RandIndex(tt.P, tt.Q)
+		ari, _ := AdjustedRandIndex(tt.P, tt.Q)
+		nmi, _ := NormalizedMutualInformation(tt.P, tt.Q)
+		vi, _ := VariationOfInformation(tt.P, tt.Q)
+		fm, _ := FowlkesMallows(tt.P, tt.Q)
+		got := []float64{ri, ari, nmi, vi, fm, tt.P.Entropy()}
+		want := []float64{tt.ri, tt.ari, tt.nmi, tt.vi, tt.fm, tt.h}
+		for k := range got {
+			if math.Abs(got[k]-want[k]) > 1e-12 {
+				t.Errorf("Case %d: expecting RI, ARI, NMI, VI, FM, H of %v instead got %v", i, want, got)
+				break
+			}
+		}
+	}
+	if _, err := RandIndex(labelled(0, 0), labelled(0, 0, 0)); err != ErrIncompatiblePartitions {
+		t.Errorf("Expecting ErrIncompatiblePartitions instead got %v", err)
+	}
+}
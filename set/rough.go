@@ -0,0 +1,340 @@
+package set
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotPartition is returned when a collection of sets is not a partition of a universe.
+var ErrNotPartition = errors.New("set: classes do not partition the universe")
+
+// ErrInvalidAttribute is returned when an attribute is not the int index of a column of an information table.
+var ErrInvalidAttribute = errors.New("set: attribute is not an index of the information table")
+
+// ErrInvalidRecord is returned when an information table record is not a Tuple of an object and its attribute values.
+var ErrInvalidRecord = errors.New("set: record must be a tuple of an object and a []interface{} of attribute values")
+
+// ApproximationSpace is a universe U together with an indiscernibility relation,
+// given as the partition of U into its equivalence classes.
+//
+// (U, R)	approximation space	elements in the same class of R cannot be told apart
+type ApproximationSpace struct {
+	U         *Set
+	partition *Partition
+}
+
+// NewApproximationSpace returns the approximation space of U partitioned into classes.
+// ErrNotPartition is returned if the classes are empty, overlap or do not cover U exactly.
+func NewApproximationSpace(U *Set, classes ...*Set) (*ApproximationSpace, error) {
+	P, err := NewPartition(U, classes...)
+	if err != nil {
+		return nil, err
+	}
+	return &ApproximationSpace{U: U, partition: P}, nil
+}
+
+// Classes returns the indiscernibility classes of S.
+func (S *ApproximationSpace) Classes() []*Set {
+	return S.partition.Blocks()
+}
+
+// Lower returns the lower approximation of X, the union of all classes contained in X.
+//
+// R̲X = ⋃{[x] : [x] ⊆ X}
+func (S *ApproximationSpace) Lower(X *Set) (C *Set) {
+	C = NewSet()
+	for _, c := range S.partition.Blocks() {
+		if c.IsSubset(X) {
+			for e := range c.E {
+				C.Add(e)
+			}
+		}
+	}
+	return
+}
+
+// Upper returns the upper approximation of X, the union of all classes that intersect X.
+//
+// R̅X = ⋃{[x] : [x] ∩ X ≠ ∅}
+func (S *ApproximationSpace) Upper(X *Set) (C *Set) {
+	C = NewSet()
+	seen := make(map[*Set]nothing)
+	for e := range X.E {
+		c, ok := S.partition.Block(e)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = nothing{}
+		for e2 := range c.E {
+			C.Add(e2)
+		}
+	}
+	return
+}
+
+// Boundary returns the boundary region of X, the elements that can neither be ruled in nor out.
+//
+// BN(X) = R̅X − R̲X
+func (S *ApproximationSpace) Boundary(X *Set) *Set {
+	return Difference(S.Upper(X), S.Lower(X))
+}
+
+// Accuracy returns the accuracy of approximation of X. It is 1 when X is exactly definable.
+// The empty set is exactly definable and so has an accuracy of 1.
+//
+// α(X) = |R̲X| / |R̅X|
+func (S *ApproximationSpace) Accuracy(X *Set) float64 {
+	upper := S.Upper(X).Cardinality()
+	if upper == 0 {
+		return 1
+	}
+	return S.Lower(X).Cardinality() / upper
+}
+
+// Quality returns the quality of approximation of X, the fraction of U certainly in X. It is NaN
+// for an empty universe.
+//
+// γ(X) = |R̲X| / |U|
+func (S *ApproximationSpace) Quality(X *Set) float64 {
+	return S.Lower(X).Cardinality() / S.U.Cardinality()
+}
+
+// InformationTable is a table of objects described by the values of a fixed number of attributes.
+// Attributes are referred to by their index, and subsets of attributes are sets of indices.
+type InformationTable struct {
+	objects    []interface{}
+	values     map[interface{}][]interface{}
+	attributes int
+}
+
+// NewInformationTable returns a table built from records. Each record is a Tuple of an object
+// and the []interface{} of its attribute values, and every record must have the same number of values.
+func NewInformationTable(records ...Tuple) (*InformationTable, error) {
+	T := &InformationTable{values: make(map[interface{}][]interface{}, len(records))}
+	for i, r := range records {
+		vals, ok := r.b.([]interface{})
+		if !ok {
+			return nil, ErrInvalidRecord
+		}
+		if _, ok := T.values[r.a]; ok {
+			return nil, ErrInvalidRecord
+		}
+		if i == 0 {
+			T.attributes = len(vals)
+		} else if len(vals) != T.attributes {
+			return nil, ErrInvalidRecord
+		}
+		T.objects = append(T.objects, r.a)
+		T.values[r.a] = vals
+	}
+	return T, nil
+}
+
+// Universe returns the set of all objects in T.
+func (T *InformationTable) Universe() *Set {
+	return NewSet(T.objects...)
+}
+
+// Attributes returns the set of all attribute indices of T.
+func (T *InformationTable) Attributes() *Set {
+	A := NewSet()
+	for i := 0; i < T.attributes; i++ {
+		A.Add(i)
+	}
+	return A
+}
+
+// Indiscernibility returns the classes of objects that have equal values for every attribute in B.
+// ErrInvalidAttribute is returned if B holds anything but the indices of attributes of T.
+//
+// IND(B) = {(x,y) ∈ U×U : a(x)=a(y) for all a∈B}
+func (T *InformationTable) Indiscernibility(B *Set) ([]*Set, error) {
+	attrs, err := T.attributeIndices(B)
+	if err != nil {
+		return nil, err
+	}
+	return T.indiscernibility(attrs), nil
+}
+
+func (T *InformationTable) indiscernibility(attrs []int) []*Set {
+	classes := [][]interface{}{T.objects}
+	if len(T.objects) == 0 {
+		classes = nil
+	}
+	for _, a := range attrs {
+		var refined [][]interface{}
+		for _, c := range classes {
+			var order []interface{}
+			groups := make(map[interface{}][]interface{})
+			for _, o := range c {
+				v := T.values[o][a]
+				if _, ok := groups[v]; !ok {
+					order = append(order, v)
+				}
+				groups[v] = append(groups[v], o)
+			}
+			for _, v := range order {
+				refined = append(refined, groups[v])
+			}
+		}
+		classes = refined
+	}
+	sets := make([]*Set, 0, len(classes))
+	for _, c := range classes {
+		sets = append(sets, NewSet(c...))
+	}
+	return sets
+}
+
+// ApproximationSpace returns the approximation space induced by the attributes in B.
+// ErrInvalidAttribute is returned if B holds anything but the indices of attributes of T.
+func (T *InformationTable) ApproximationSpace(B *Set) (*ApproximationSpace, error) {
+	attrs, err := T.attributeIndices(B)
+	if err != nil {
+		return nil, err
+	}
+	return T.approximationSpace(attrs), nil
+}
+
+func (T *InformationTable) approximationSpace(attrs []int) *ApproximationSpace {
+	S, _ := NewApproximationSpace(T.Universe(), T.indiscernibility(attrs)...)
+	return S
+}
+
+// PositiveRegion returns the objects whose D values are determined by their C values.
+// ErrInvalidAttribute is returned if C or D holds anything but the indices of attributes of T.
+//
+// POS_C(D) = ⋃{C̲X : X ∈ U/IND(D)}
+func (T *InformationTable) PositiveRegion(C, D *Set) (*Set, error) {
+	c, d, err := T.conditionDecision(C, D)
+	if err != nil {
+		return nil, err
+	}
+	return T.positiveRegion(c, d), nil
+}
+
+func (T *InformationTable) positiveRegion(c, d []int) (P *Set) {
+	P = NewSet()
+	S := T.approximationSpace(c)
+	for _, X := range T.indiscernibility(d) {
+		for e := range S.Lower(X).E {
+			P.Add(e)
+		}
+	}
+	return
+}
+
+// Dependency returns the degree to which the attributes in D depend on the attributes in C.
+// It is NaN for a table with no objects.
+// ErrInvalidAttribute is returned if C or D holds anything but the indices of attributes of T.
+//
+// γ_C(D) = |POS_C(D)| / |U|
+func (T *InformationTable) Dependency(C, D *Set) (float64, error) {
+	P, err := T.PositiveRegion(C, D)
+	if err != nil {
+		return 0, err
+	}
+	return P.Cardinality() / float64(len(T.objects)), nil
+}
+
+// Reducts returns every minimal subset R of C that preserves the dependency of D on C,
+// i.e. POS_R(D) = POS_C(D). Passing D = C gives the reducts of the information table itself.
+// Reducts are returned in order of increasing size.
+// ErrInvalidAttribute is returned if C or D holds anything but the indices of attributes of T.
+func (T *InformationTable) Reducts(C, D *Set) (reducts []*Set, err error) {
+	attrs, d, err := T.conditionDecision(C, D)
+	if err != nil {
+		return nil, err
+	}
+	target := T.positiveRegion(attrs, d).Len()
+	for size := 0; size <= len(attrs); size++ {
+		combinations(len(attrs), size, func(idx []int) {
+			R := NewSet()
+			r := make([]int, len(idx))
+			for i, j := range idx {
+				R.Add(attrs[j])
+				r[i] = attrs[j]
+			}
+			for _, reduct := range reducts {
+				if reduct.IsSubset(R) {
+					return
+				}
+			}
+			if T.positiveRegion(r, d).Len() == target {
+				reducts = append(reducts, R)
+			}
+		})
+	}
+	return reducts, nil
+}
+
+// Core returns the attributes of C that are indispensable for D, which is the intersection of all reducts.
+// ErrInvalidAttribute is returned if C or D holds anything but the indices of attributes of T.
+//
+// CORE_C(D) = {a ∈ C : POS_{C−{a}}(D) ≠ POS_C(D)}
+func (T *InformationTable) Core(C, D *Set) (*Set, error) {
+	attrs, d, err := T.conditionDecision(C, D)
+	if err != nil {
+		return nil, err
+	}
+	K := NewSet()
+	target := T.positiveRegion(attrs, d).Len()
+	for i, a := range attrs {
+		without := append(append([]int(nil), attrs[:i]...), attrs[i+1:]...)
+		if T.positiveRegion(without, d).Len() != target {
+			K.Add(a)
+		}
+	}
+	return K, nil
+}
+
+// conditionDecision returns the sorted attribute indices of the condition attributes C and the
+// decision attributes D.
+func (T *InformationTable) conditionDecision(C, D *Set) (c, d []int, err error) {
+	if c, err = T.attributeIndices(C); err != nil {
+		return nil, nil, err
+	}
+	if d, err = T.attributeIndices(D); err != nil {
+		return nil, nil, err
+	}
+	return c, d, nil
+}
+
+// attributeIndices returns the attributes in B in increasing order.
+func (T *InformationTable) attributeIndices(B *Set) ([]int, error) {
+	attrs := make([]int, 0, len(B.E))
+	for a := range B.E {
+		i, ok := a.(int)
+		if !ok || i < 0 || i >= T.attributes {
+			return nil, ErrInvalidAttribute
+		}
+		attrs = append(attrs, i)
+	}
+	sort.Ints(attrs)
+	return attrs, nil
+}
+
+// combinations calls fn with every k-combination of the indices 0..n-1 in lexicographic order.
+func combinations(n, k int, fn func(idx []int)) {
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		fn(idx)
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
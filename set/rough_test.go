@@ -0,0 +1,144 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+// Pawlak's flu table: headache, muscle pain, temperature, flu.
+func fluTable(t *testing.T) *InformationTable {
+	t.Helper()
+	T, err := NewInformationTable(
+		NewTuple(1, []interface{}{"yes", "yes", "normal", "no"}),
+		NewTuple(2, []interface{}{"yes", "yes", "high", "yes"}),
+		NewTuple(3, []interface{}{"yes", "yes", "very high", "yes"}),
+		NewTuple(4, []interface{}{"no", "yes", "normal", "no"}),
+		NewTuple(5, []interface{}{"no", "no", "high", "no"}),
+		NewTuple(6, []interface{}{"no", "yes", "very high", "yes"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return T
+}
+
+func Test_NewApproximationSpace(t *testing.T) {
+	U := NewSet(1, 2, 3, 4)
+	if _, err := NewApproximationSpace(U, NewSet(1, 2), NewSet(3, 4)); err != nil {
+		t.Errorf("Expecting a valid partition instead got %v", err)
+	}
+	if _, err := NewApproximationSpace(U, NewSet(1, 2), NewSet(2, 3, 4)); err != ErrNotPartition {
+		t.Errorf("Expecting overlapping classes to be rejected instead got %v", err)
+	}
+	if _, err := NewApproximationSpace(U, NewSet(1, 2), NewSet(3)); err != ErrNotPartition {
+		t.Errorf("Expecting classes that do not cover U to be rejected instead got %v", err)
+	}
+	if _, err := NewApproximationSpace(U, NewSet(1, 2), NewSet(3, 4, 5)); err != ErrNotPartition {
+		t.Errorf("Expecting classes outside of U to be rejected instead got %v", err)
+	}
+}
+
+func Test_Approximations(t *testing.T) {
+	U := NewSet(1, 2, 3, 4, 5, 6)
+	S, err := NewApproximationSpace(U, NewSet(1, 4), NewSet(2, 5), NewSet(3, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	X := NewSet(2, 3, 6)
+	if L := S.Lower(X); !L.IsEqual(NewSet(3, 6)) {
+		t.Errorf("Expecting a lower approximation of {3, 6} instead got %v", L)
+	}
+	if R := S.Upper(X); !R.IsEqual(NewSet(2, 3, 5, 6)) {
+		t.Errorf("Expecting an upper approximation of {2, 3, 5, 6} instead got %v", R)
+	}
+	if B := S.Boundary(X); !B.IsEqual(NewSet(2, 5)) {
+		t.Errorf("Expecting a boundary of {2, 5} instead got %v", B)
+	}
+	if a := S.Accuracy(X); a != 0.5 {
+		t.Errorf("Expecting an accuracy of 0.5 instead got %f", a)
+	}
+	if q := S.Quality(X); math.Abs(q-1.0/3) > 1e-9 {
+		t.Errorf("Expecting a quality of 1/3 instead got %f", q)
+	}
+	if a := S.Accuracy(NewSet()); a != 1 {
+		t.Errorf("Expecting the empty set to have an accuracy of 1 instead got %f", a)
+	}
+}
+
+func Test_InformationTable(t *testing.T) {
+	if _, err := NewInformationTable(NewTuple(1, "yes")); err != ErrInvalidRecord {
+		t.Errorf("Expecting ErrInvalidRecord instead got %v", err)
+	}
+	if _, err := NewInformationTable(NewTuple(1, []interface{}{1, 2}), NewTuple(2, []interface{}{1})); err != ErrInvalidRecord {
+		t.Errorf("Expecting ErrInvalidRecord for ragged records instead got %v", err)
+	}
+	T := fluTable(t)
+	classes, err := T.Indiscernibility(NewSet(0, 1))
+	if err != nil || len(classes) != 3 {
+		t.Errorf("Expecting 3 classes for headache & muscle pain instead got %v", classes)
+	}
+	S, err := T.ApproximationSpace(NewSet(0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flu := NewSet(2, 3, 6)
+	if L := S.Lower(flu); L.Cardinality() != 0 {
+		t.Errorf("Expecting an empty lower approximation instead got %v", L)
+	}
+	if R := S.Upper(flu); !R.IsEqual(NewSet(1, 2, 3, 4, 6)) {
+		t.Errorf("Expecting an upper approximation of {1, 2, 3, 4, 6} instead got %v", R)
+	}
+}
+
+func Test_Reducts(t *testing.T) {
+	T := fluTable(t)
+	C := NewSet(0, 1, 2)
+	D := NewSet(3)
+	if g, err := T.Dependency(C, D); err != nil || g != 1 {
+		t.Errorf("Expecting flu to depend fully on the symptoms instead got %f", g)
+	}
+	if g, _ := T.Dependency(NewSet(2), D); math.Abs(g-2.0/3) > 1e-9 {
+		t.Errorf("Expecting a dependency of 2/3 on temperature instead got %f", g)
+	}
+	reducts, err := T.Reducts(C, D)
+	if err != nil || len(reducts) != 2 || !reducts[0].IsEqual(NewSet(0, 2)) || !reducts[1].IsEqual(NewSet(1, 2)) {
+		t.Errorf("Expecting the reducts {0, 2} & {1, 2} instead got %v", reducts)
+	}
+	core, err := T.Core(C, D)
+	if err != nil || !core.IsEqual(NewSet(2)) {
+		t.Errorf("Expecting a core of {2} instead got %v", core)
+	}
+	if I := Intersect(reducts[0], reducts[1]); !I.IsEqual(core) {
+		t.Errorf("Expecting the core %v to be the intersection of all reducts %v", core, I)
+	}
+}
+
+func Test_InvalidAttributes(t *testing.T) {
+	T := fluTable(t)
+	for _, B := range []*Set{NewSet("0"), NewSet(4), NewSet(-1), NewSet(0, 2.5)} {
+		if _, err := T.Indiscernibility(B); err != ErrInvalidAttribute {
+			t.Errorf("Expecting ErrInvalidAttribute from Indiscernibility(%v) instead got %v", B, err)
+		}
+		if _, err := T.ApproximationSpace(B); err != ErrInvalidAttribute {
+			t.Errorf("Expecting ErrInvalidAttribute from ApproximationSpace(%v) instead got %v", B, err)
+		}
+		for _, CD := range [][2]*Set{{B, NewSet(3)}, {NewSet(0), B}} {
+			if _, err := T.PositiveRegion(CD[0], CD[1]); err != ErrInvalidAttribute {
+				t.Errorf("Expecting ErrInvalidAttribute from PositiveRegion(%v, %v) instead got %v", CD[0], CD[1], err)
+			}
+			if _, err := T.Dependency(CD[0], CD[1]); err != ErrInvalidAttribute {
+				t.Errorf("Expecting ErrInvalidAttribute from Dependency(%v, %v) instead got %v", CD[0], CD[1], err)
+			}
+			if _, err := T.Reducts(CD[0], CD[1]); err != ErrInvalidAttribute {
+				t.Errorf("Expecting ErrInvalidAttribute from Reducts(%v, %v) instead got %v", CD[0], CD[1], err)
+			}
+			if _, err := T.Core(CD[0], CD[1]); err != ErrInvalidAttribute {
+				t.Errorf("Expecting ErrInvalidAttribute from Core(%v, %v) instead got %v", CD[0], CD[1], err)
+			}
+		}
+	}
+	empty, _ := NewInformationTable()
+	if g, err := empty.Dependency(NewSet(), NewSet()); err != nil || !math.IsNaN(g) {
+		t.Errorf("Expecting a dependency of NaN for an empty table instead got %v, %v", g, err)
+	}
+}
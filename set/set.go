@@ -1,4 +1,10 @@
 // Package set represents the mathematical set.
+//
+// The package depends only on the standard library, so its text normalisation does not provide
+// NFKC or Unicode case folding: CompatibilityFold covers only the common compatibility
+// characters and LowerCase maps rune by rune. For either, pass norm.NFKC.String from
+// golang.org/x/text/unicode/norm or cases.Fold().String from golang.org/x/text/cases to
+// WithNormalizer.
 package set
 
 import (
@@ -13,6 +19,21 @@ type Tuple struct {
 	a, b interface{}
 }
 
+// NewTuple returns the ordered pair (a,b).
+func NewTuple(a, b interface{}) Tuple {
+	return Tuple{a, b}
+}
+
+// First returns the first element of the ordered pair.
+func (t Tuple) First() interface{} {
+	return t.a
+}
+
+// Second returns the second element of the ordered pair.
+func (t Tuple) Second() interface{} {
+	return t.b
+}
+
 // String returns a string representation of a tuple
 func (t *Tuple) String() (s string) {
 	s = fmt.Sprintf("(%v,%v)", t.a, t.b)
@@ -31,6 +52,22 @@ type Set struct {
 	sync.Mutex
 }
 
+// Interface is the small read/write API shared by every set backend in this package, such as Set
+// and SmallSet. The set operations and similarity metrics are written against it, with fast paths
+// when both operands share a backend.
+type Interface interface {
+	// Contains checks if one or more elements are in the set.
+	Contains(els ...interface{}) bool
+	// Len returns the number of elements in the set.
+	Len() int
+	// Iterate calls fn for every element of the set until fn returns false.
+	Iterate(fn func(e interface{}) bool)
+	// Add inserts one or more elements into the set.
+	Add(els ...interface{})
+	// Remove deletes one or more existing elements from the set.
+	Remove(els ...interface{})
+}
+
 func new() (A Set) {
 	return
 }
@@ -74,6 +111,22 @@ func (A *Set) Remove(els ...interface{}) {
 	}
 }
 
+// Len returns the number of elements in A.
+func (A *Set) Len() int {
+	A.Lock()
+	defer A.Unlock()
+	return len(A.E)
+}
+
+// Iterate calls fn for every element of A until fn returns false.
+func (A *Set) Iterate(fn func(e interface{}) bool) {
+	for e := range A.E {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
 // SetToSlice converts a set to a slice.
 func (A *Set) SetToSlice() []interface{} {
 	ss := make([]interface{}, 0, len(A.E))
@@ -125,7 +178,7 @@ func (A *Set) Cardinality() float64 {
 // A={1,2}
 // B={2,1,4,3,5}
 // A⊆B
-func (A *Set) Subset(B *Set) (C Set) {
+func (A *Set) Subset(B Interface) (C Set) {
 	return Subset(A, B)
 }
 
@@ -136,25 +189,26 @@ func (A *Set) Subset(B *Set) (C Set) {
 // IsDisjoint
 //
 // Two sets are disjoint sets if there are no common elements in both sets. Example: A = {1,2,3,4} B = {5,6,7,8}. Here, set A and set B are disjoint sets.
-func (A *Set) IsDisjoint(B *Set) bool {
-	return Intersect(A, B).Cardinality() == 0
+func (A *Set) IsDisjoint(B Interface) bool {
+	return intersectionLen(A, B) == 0
 }
 
 // IsEquivalent checks if A & B have the same Cardinality.
 //
 // Sets are equivalent when their cardinality is the same. NOT to be mistaken with equality.
-func (A *Set) IsEquivalent(B *Set) bool {
-	return A.Cardinality() == B.Cardinality()
+func (A *Set) IsEquivalent(B Interface) bool {
+	return A.Len() == B.Len()
 }
 
 // Equals
 // If two sets have the same elements in them, then they are called equal sets. Example: A = {1,2,3} and B = {1,2,3}. Here, set A and set B are equal sets. This can be represented as A = B.
-func (A *Set) IsEqual(B *Set) bool {
+func (A *Set) IsEqual(B Interface) bool {
 	return Equals(A, B)
 }
 
-func Equals(A, B *Set) bool {
-	return A.IsEquivalent(B) && A.IsSubset(B)
+// Equals checks if A & B have the same elements.
+func Equals(A, B Interface) bool {
+	return A.Len() == B.Len() && isSubset(A, B)
 }
 
 // IsSubset checks if A is a subset of B.
@@ -163,16 +217,30 @@ func Equals(A, B *Set) bool {
 // A={1,2}
 // B={2,1,4,3,5}
 // A⊆B
-func (A *Set) IsSubset(B *Set) bool {
-	if A.Cardinality() > B.Cardinality() {
+func (A *Set) IsSubset(B Interface) bool {
+	return isSubset(A, B)
+}
+
+func isSubset(A, B Interface) bool {
+	if A.Len() > B.Len() {
 		return false
 	}
-	for e := range A.E {
-		if !B.Contains(e) {
-			return false
+	if a, ok := A.(*Set); ok {
+		if b, ok := B.(*Set); ok {
+			for e := range a.E {
+				if _, ok := b.E[e]; !ok {
+					return false
+				}
+			}
+			return true
 		}
 	}
-	return true
+	subset := true
+	A.Iterate(func(e interface{}) bool {
+		subset = B.Contains(e)
+		return subset
+	})
+	return subset
 }
 
 // IsProperSubset checks if the A is a proper subset of B.
@@ -180,8 +248,8 @@ func (A *Set) IsSubset(B *Set) bool {
 // A={1,2,3,4,5}
 // B={2,1,4,3,5}
 // A⊆B is true but A⊂B is not true
-func (A *Set) IsProperSubset(B *Set) bool {
-	return A.Cardinality() < B.Cardinality() && A.IsSubset(B)
+func (A *Set) IsProperSubset(B Interface) bool {
+	return A.Len() < B.Len() && A.IsSubset(B)
 }
 
 // IsSuperset checks if A is a superset of B.
@@ -189,8 +257,8 @@ func (A *Set) IsProperSubset(B *Set) bool {
 // A={2,4,6,7,8}
 // B={2,4,8}
 // A⊇B
-func (A *Set) IsSuperset(B *Set) bool {
-	return B.IsSubset(A)
+func (A *Set) IsSuperset(B Interface) bool {
+	return isSubset(B, A)
 }
 
 // IsProperSuperset checks if A is a proper superset of B.
@@ -198,8 +266,8 @@ func (A *Set) IsSuperset(B *Set) bool {
 // A={2,4,6,7,8}
 // B={2,4,8}
 // A⊇B
-func (A *Set) IsProperSuperset(B *Set) bool {
-	return A.Cardinality() > B.Cardinality() && A.IsSuperset(B)
+func (A *Set) IsProperSuperset(B Interface) bool {
+	return A.Len() > B.Len() && A.IsSuperset(B)
 }
 
 // Operations and Functions
@@ -209,7 +277,7 @@ func (A *Set) IsProperSuperset(B *Set) bool {
 // A={1,2}
 // B={2,3,5}
 // A∪B={1,2,3,5}
-func (A *Set) Union(B *Set) (C *Set) {
+func (A *Set) Union(B Interface) (C *Set) {
 	return Union(A, B)
 }
 
@@ -218,7 +286,7 @@ func (A *Set) Union(B *Set) (C *Set) {
 // A={1,2}
 // B={2,3,5}
 // A∩B={2}
-func (A *Set) Intersect(B *Set) (C *Set) {
+func (A *Set) Intersect(B Interface) (C *Set) {
 	return Intersect(A, B)
 }
 
@@ -227,38 +295,89 @@ func (A *Set) Intersect(B *Set) (C *Set) {
 // A={1,2}
 // B={2,3,5}
 // A∩B={2}
-func Intersect(A, B *Set) (C *Set) {
+func Intersect(A, B Interface) (C *Set) {
 	C = NewSet()
-	if A.Cardinality() < B.Cardinality() {
-		for e := range A.E {
-			if B.Contains(e) {
-				C.Add(e)
+	if A.Len() > B.Len() {
+		A, B = B, A
+	}
+	if a, ok := A.(*Set); ok {
+		if b, ok := B.(*Set); ok {
+			for e := range a.E {
+				if _, ok := b.E[e]; ok {
+					C.Add(e)
+				}
 			}
+			return
 		}
-		return
 	}
-	for e := range B.E {
-		if A.Contains(e) {
-			C.Add(e)
+	each(A, B, true, C.Add)
+	return
+}
+
+// intersectionLen returns |A∩B| without building the intersection.
+func intersectionLen(A, B Interface) (n int) {
+	if A.Len() > B.Len() {
+		A, B = B, A
+	}
+	if a, ok := A.(*Set); ok {
+		if b, ok := B.(*Set); ok {
+			for e := range a.E {
+				if _, ok := b.E[e]; ok {
+					n++
+				}
+			}
+			return
 		}
 	}
+	if a, ok := A.(*SmallSet); ok {
+		if b, ok := B.(*SmallSet); ok {
+			a.each(func(e interface{}) bool {
+				if b.has(e) {
+					n++
+				}
+				return true
+			})
+			return
+		}
+	}
+	each(A, B, true, func(...interface{}) { n++ })
 	return
 }
 
+// each calls fn with every element of A that is (in) or is not (!in) an element of B.
+func each(A, B Interface, in bool, fn func(els ...interface{})) {
+	A.Iterate(func(e interface{}) bool {
+		if B.Contains(e) == in {
+			fn(e)
+		}
+		return true
+	})
+}
+
+// addAll inserts every element of B into A.
+func addAll(A *Set, B Interface) {
+	if b, ok := B.(*Set); ok {
+		for e := range b.E {
+			A.E[e] = nothing{}
+		}
+		return
+	}
+	B.Iterate(func(e interface{}) bool {
+		A.Add(e)
+		return true
+	})
+}
+
 // Union creates a new set (C) from elements in A & B.
 // ∪ 	union	a set with the elements in set A or in set B
 // A={1,2}
 // B={2,3,5}
 // A∪B={1,2,3,5}
-func Union(A, B *Set) (C *Set) {
+func Union(A, B Interface) (C *Set) {
 	//TODO add go routines to do both in parallel
 	C = NewSet()
-	for e := range A.E {
-		C.Add(e)
-	}
-	for e := range B.E {
-		C.Add(e)
-	}
+	addAll(C, A)
+	addAll(C, B)
 	return
 }
 
@@ -268,18 +387,24 @@ func Union(A, B *Set) (C *Set) {
 // B={2,3,5,8}
 // A−B={1,4}
 // B−A={5,8}
-func Difference(A, B *Set) (C *Set) {
+func Difference(A, B Interface) (C *Set) {
 	C = NewSet()
-	for e := range A.E {
-		if !B.Contains(e) {
-			C.Add(e)
+	if a, ok := A.(*Set); ok {
+		if b, ok := B.(*Set); ok {
+			for e := range a.E {
+				if _, ok := b.E[e]; !ok {
+					C.Add(e)
+				}
+			}
+			return
 		}
 	}
+	each(A, B, false, C.Add)
 	return
 }
 
 // SymetricDifferencec creates a new set (C) from elements in A only AND elements in B only
-func SymetricDifferencec(A, B *Set) (C *Set) {
+func SymetricDifferencec(A, B Interface) (C *Set) {
 	C = Difference(A, B).Union(Difference(B, A))
 	return
 }
@@ -290,25 +415,19 @@ func SymetricDifferencec(A, B *Set) (C *Set) {
 // A={1,2}
 // B={2,1,4,3,5}
 // A⊆B
-func Subset(A, B *Set) (C Set) {
-	l := math.Max(A.Cardinality(), B.Cardinality())
+func Subset(A, B Interface) (C Set) {
+	l := math.Max(float64(A.Len()), float64(B.Len()))
 	C.E = make(elements, int(l))
-	for e := range A.E {
-		if B.Contains(e) {
-			C.Add(e)
-		}
-	}
+	each(A, B, true, C.Add)
 	return
 }
 
 // Complement
 // When all sets in the universe, i.e. all sets under consideration, are considered to be members of a given set U, the absolute complement of A is the set of elements in U that are not in A.
-func Complement(A *Set, U ...*Set) *Set {
+func Complement(A Interface, U ...Interface) *Set {
 	universe := NewSet()
 	for _, s := range U {
-		for e := range s.E {
-			universe.Add(e)
-		}
+		addAll(universe, s)
 	}
 	return Difference(A, universe)
 }
@@ -343,24 +462,27 @@ func (A *Set) PowersetCardinality() int {
 // B={3,4}
 // A×B={(1,3),(2,3),(1,4),(2,4)}
 // B×A={(3,1),(3,2),(4,1),(4,2)}
-func CartesianProduct(A, B *Set) (C *Set) {
+func CartesianProduct(A, B Interface) (C *Set) {
 	C = NewSet()
-	for e := range A.E {
-		for e2 := range B.E {
+	A.Iterate(func(e interface{}) bool {
+		B.Iterate(func(e2 interface{}) bool {
 			comb := Tuple{e, e2}
 			C.Add(comb)
-		}
-	}
+			return true
+		})
+		return true
+	})
 	return
 }
 
 // DisjointUnion
-func DisjointUnion(sets ...*Set) (C *Set) {
+func DisjointUnion(sets ...Interface) (C *Set) {
 	C = NewSet()
 	for i := range sets {
-		for e := range sets[i].E {
+		sets[i].Iterate(func(e interface{}) bool {
 			C.Add(Tuple{e, i})
-		}
+			return true
+		})
 	}
 	return
 }
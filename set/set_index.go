@@ -0,0 +1,218 @@
+package set
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// SetIndex is an inverted index of sets stored under ids. It maps every element to the ids of
+// the sets holding it, so that queries count their overlap with the stored sets from the
+// postings of their own elements rather than by comparing sets.
+// Sets are copied on insertion, so later changes to them do not affect the index.
+type SetIndex struct {
+	sets     map[string][]interface{}
+	postings map[interface{}]map[string]nothing
+	empty    map[string]nothing
+}
+
+// NewSetIndex returns an empty index.
+func NewSetIndex() *SetIndex {
+	return &SetIndex{
+		sets:     make(map[string][]interface{}),
+		postings: make(map[interface{}]map[string]nothing),
+		empty:    make(map[string]nothing),
+	}
+}
+
+// Len returns the number of sets in I.
+func (I *SetIndex) Len() int {
+	return len(I.sets)
+}
+
+// Insert stores A under id, replacing any set already stored under it.
+func (I *SetIndex) Insert(id string, A Interface) {
+	I.Remove(id)
+	els := make([]interface{}, 0, A.Len())
+	A.Iterate(func(e interface{}) bool {
+		els = append(els, e)
+		p, ok := I.postings[e]
+		if !ok {
+			p = make(map[string]nothing)
+			I.postings[e] = p
+		}
+		p[id] = nothing{}
+		return true
+	})
+	I.sets[id] = els
+	if len(els) == 0 {
+		I.empty[id] = nothing{}
+	}
+}
+
+// Remove deletes the set stored under id, and reports whether there was one.
+func (I *SetIndex) Remove(id string) bool {
+	els, ok := I.sets[id]
+	if !ok {
+		return false
+	}
+	for _, e := range els {
+		p := I.postings[e]
+		delete(p, id)
+		if len(p) == 0 {
+			delete(I.postings, e)
+		}
+	}
+	delete(I.sets, id)
+	delete(I.empty, id)
+	return true
+}
+
+// overlaps returns the number of elements of Q in each stored set sharing any with it.
+func (I *SetIndex) overlaps(Q Interface) map[string]int {
+	counts := make(map[string]int)
+	Q.Iterate(func(e interface{}) bool {
+		for id := range I.postings[e] {
+			counts[id]++
+		}
+		return true
+	})
+	return counts
+}
+
+// scored is a stored set with its similarity to a query.
+type scored struct {
+	id    string
+	score float64
+}
+
+// worstFirst is a heap of scored sets with the lowest score, then the highest id, on top.
+type worstFirst []scored
+
+// worse checks if s ranks below t: it has a lower score, or an equal score and a higher id.
+func (s scored) worse(t scored) bool {
+	if s.score != t.score {
+		return s.score < t.score
+	}
+	return s.id > t.id
+}
+
+func (h worstFirst) Len() int            { return len(h) }
+func (h worstFirst) Less(i, j int) bool  { return h[i].worse(h[j]) }
+func (h worstFirst) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *worstFirst) Push(x interface{}) { *h = append(*h, x.(scored)) }
+func (h *worstFirst) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// TopK returns the ids of the k stored sets most similar to Q under m, as Tuples of the id and
+// the similarity, from most to least similar and by id among equal similarities. Sets whose
+// similarity to Q is undefined are left out, unless opts give it a value.
+// Errors are returned as for PairwiseSimilarity, with ErrNotInUniverse if Q or a stored set is
+// not a subset of the universe of m.
+//
+// Overlaps are counted from the postings of the elements of Q, so the stored sets themselves
+// are never compared, but every stored set is scored: sets sharing nothing with Q from their
+// size alone. A query costs O(Σ postings of Q + |I| log k).
+func (I *SetIndex) TopK(Q Interface, k int, m Measure, opts ...SimilarityOption) ([]Tuple, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	if m.overUniverse() {
+		if !isSubset(Q, m.universe) {
+			return nil, ErrNotInUniverse
+		}
+		for e := range I.postings {
+			if !m.universe.Contains(e) {
+				return nil, ErrNotInUniverse
+			}
+		}
+	}
+	if k < 1 {
+		return nil, nil
+	}
+	counts := I.overlaps(Q)
+	q := float64(Q.Len())
+	h := make(worstFirst, 0, k+1)
+	for id, els := range I.sets {
+		a := float64(counts[id])
+		s := scored{id, m.fromCounts(a, q-a, float64(len(els))-a, opts)}
+		if math.IsNaN(s.score) {
+			continue
+		}
+		if len(h) < k {
+			heap.Push(&h, s)
+		} else if h[0].worse(s) {
+			h[0] = s
+			heap.Fix(&h, 0)
+		}
+	}
+	top := make([]Tuple, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		s := heap.Pop(&h).(scored)
+		top[i] = NewTuple(s.id, s.score)
+	}
+	return top, nil
+}
+
+// Supersets returns the ids of the stored sets that are supersets of Q, in sorted order.
+func (I *SetIndex) Supersets(Q Interface) []string {
+	var ids []string
+	if Q.Len() == 0 {
+		for id := range I.sets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+	// Every superset holds the element of Q with the fewest postings, so only those are checked.
+	var rarest map[string]nothing
+	missing := false
+	Q.Iterate(func(e interface{}) bool {
+		p, ok := I.postings[e]
+		if !ok {
+			missing = true
+			return false
+		}
+		if rarest == nil || len(p) < len(rarest) {
+			rarest = p
+		}
+		return true
+	})
+	if missing {
+		return nil
+	}
+	for id := range rarest {
+		if len(I.sets[id]) < Q.Len() {
+			continue
+		}
+		all := true
+		Q.Iterate(func(e interface{}) bool {
+			_, all = I.postings[e][id]
+			return all
+		})
+		if all {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Subsets returns the ids of the stored sets that are subsets of Q, in sorted order.
+func (I *SetIndex) Subsets(Q Interface) []string {
+	var ids []string
+	for id, n := range I.overlaps(Q) {
+		if n == len(I.sets[id]) {
+			ids = append(ids, id)
+		}
+	}
+	for id := range I.empty {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,106 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// bruteTopK ranks every set in C by similarity to Q, as SetIndex.TopK does.
+func bruteTopK(C map[string]*Set, Q *Set, k int, m Measure) []Tuple {
+	var all []scored
+	for id, S := range C {
+		if s := m.Similarity(Q, S); !math.IsNaN(s) {
+			all = append(all, scored{id, s})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[j].worse(all[i]) })
+	if len(all) > k {
+		all = all[:k]
+	}
+	top := make([]Tuple, len(all))
+	for i, s := range all {
+		top[i] = NewTuple(s.id, s.score)
+	}
+	return top
+}
+
+func Test_SetIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	I := NewSetIndex()
+	stored := make(map[string]*Set)
+	randomSet := func() *Set {
+		S := NewSet()
+		for n := r.Intn(15); S.Len() < n; {
+			S.Add(r.Intn(40))
+		}
+		return S
+	}
+	for step := 0; step < 400; step++ {
+		id := fmt.Sprintf("s%d", r.Intn(120))
+		if r.Intn(4) == 0 {
+			if _, ok := stored[id]; I.Remove(id) != ok {
+				t.Fatalf("Expecting Remove(%s) to report %v", id, ok)
+			}
+			delete(stored, id)
+		} else {
+			S := randomSet()
+			I.Insert(id, S)
+			stored[id] = S
+		}
+		if step%20 != 0 {
+			continue
+		}
+		if I.Len() != len(stored) {
+			t.Fatalf("Expecting %d sets instead got %d", len(stored), I.Len())
+		}
+		Q := randomSet()
+		for _, m := range []Measure{Jaccard, Overlap, Dice, Tversky(1, 0.5), SimpleMatching(intSet(0, 40))} {
+			for _, k := range []int{1, 10, 200} {
+				got, err := I.TopK(Q, k, m)
+				if want := bruteTopK(stored, Q, k, m); err != nil || fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatalf("%v top %d of %v: expecting %v instead got %v", m, k, Q, want, got)
+				}
+			}
+		}
+		var supersets, subsets []string
+		for id, S := range stored {
+			if Q.IsSubset(S) {
+				supersets = append(supersets, id)
+			}
+			if S.IsSubset(Q) {
+				subsets = append(subsets, id)
+			}
+		}
+		sort.Strings(supersets)
+		sort.Strings(subsets)
+		if got := I.Supersets(Q); fmt.Sprint(got) != fmt.Sprint(supersets) {
+			t.Errorf("Supersets of %v: expecting %v instead got %v", Q, supersets, got)
+		}
+		if got := I.Subsets(Q); fmt.Sprint(got) != fmt.Sprint(subsets) {
+			t.Errorf("Subsets of %v: expecting %v instead got %v", Q, subsets, got)
+		}
+	}
+}
+
+func Test_SetIndexCopiesSets(t *testing.T) {
+	I := NewSetIndex()
+	A := NewSet(1, 2)
+	I.Insert("a", A)
+	A.Add(3)
+	if got := I.Supersets(NewSet(3)); len(got) != 0 {
+		t.Errorf("Expecting the index to be unaffected by changes to A instead got %v", got)
+	}
+	top, _ := I.TopK(NewSet(1, 2), 1, Jaccard)
+	if len(top) != 1 || top[0].First() != "a" || top[0].Second() != 1.0 {
+		t.Errorf("Expecting a with a similarity of 1 instead got %v", top)
+	}
+	if _, err := I.TopK(NewSet(1, 2), 1, Measure{}); err != ErrUnsupportedMeasure {
+		t.Errorf("Expecting ErrUnsupportedMeasure instead got %v", err)
+	}
+	if _, err := I.TopK(NewSet(1), 1, Yule(NewSet(1))); err != ErrNotInUniverse {
+		t.Errorf("Expecting ErrNotInUniverse instead got %v", err)
+	}
+}
@@ -1,37 +1,411 @@
 package set
 
-import "math"
+import (
+	"errors"
+	"math"
+)
+
+// ErrNegativeWeight is returned when a weight of the Tversky index is negative or NaN.
+var ErrNegativeWeight = errors.New("set: Tversky weights must not be negative")
+
+// ErrNotInUniverse is returned when a set is not a subset of the universe a measure counts over.
+var ErrNotInUniverse = errors.New("set: sets are not subsets of the universe")
 
 // JaccardSimilarity
 // Jaccard Index = (the number in both sets) / (the number in either set)
 //
 // The same formula in notation is:
 // J(A,B) = |A∩B| / |A∪B|
-func JaccardSimilarity(A, B *Set) float64 {
-	D := A.Intersect(B)
-	U := A.Union(B)
-	return D.Cardinality() / U.Cardinality()
+//
+// It is undefined, NaN unless opts say otherwise, when A & B are both empty.
+func JaccardSimilarity(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return jaccard(a, b, c, opts)
+}
+
+func jaccard(a, b, c float64, opts []SimilarityOption) float64 {
+	if a+b+c == 0 {
+		return undefined("JaccardSimilarity", math.NaN(), opts)
+	}
+	return a / (a + b + c)
 }
 
 // JaccardDistance
 // Jaccard distance = 1 - JaccardSimilarity
-func JaccardDistance(A, B *Set) float64 {
-	return 1 - JaccardSimilarity(A, B)
+func JaccardDistance(A, B Interface, opts ...SimilarityOption) float64 {
+	return 1 - JaccardSimilarity(A, B, opts...)
 }
 
 // DSC
 // Dice Similarity Coefficient / The Sorensen Coefficient
 // DSC equals twice the number of elements common to both sets divided by the sum of the number of elements in each set.
-func DSC(A, B *Set) float64 {
-	commonElements := A.Intersect(B).Cardinality()
-	sumOfElements := A.Cardinality() + B.Cardinality()
-	return commonElements * 2 / sumOfElements
+// It is undefined, NaN unless opts say otherwise, when A & B are both empty.
+func DSC(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return dice(a, b, c, opts)
+}
+
+func dice(a, b, c float64, opts []SimilarityOption) float64 {
+	sumOfElements := 2*a + b + c
+	if sumOfElements == 0 {
+		return undefined("DSC", math.NaN(), opts)
+	}
+	return a * 2 / sumOfElements
 }
 
 // OverlapCoefficient
 // The Overlap Coefficient is defined as the size of the intersection divided by the size of the smaller of the two sets.
-func OverlapCoefficient(A, B *Set) float64 {
-	commonElements := A.Intersect(B).Cardinality()
-	min := math.Min(A.Cardinality(), B.Cardinality())
-	return commonElements / min
+// It is undefined, NaN unless opts say otherwise, when either set is empty.
+func OverlapCoefficient(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return overlap(a, b, c, opts)
+}
+
+func overlap(a, b, c float64, opts []SimilarityOption) float64 {
+	min := math.Min(a+b, a+c)
+	if min == 0 {
+		return undefined("OverlapCoefficient", math.NaN(), opts)
+	}
+	return a / min
+}
+
+// matches returns the number of elements in both A & B (a), only in A (b) and only in B (c).
+func matches(A, B Interface) (a, b, c float64) {
+	n := intersectionLen(A, B)
+	return float64(n), float64(A.Len() - n), float64(B.Len() - n)
+}
+
+// matchesIn returns the counts of matches along with the number of elements of U in neither A
+// nor B (d). A & B must be subsets of U for d to be right.
+func matchesIn(U, A, B Interface) (a, b, c, d float64) {
+	a, b, c = matches(A, B)
+	return a, b, c, float64(U.Len()) - a - b - c
+}
+
+// TverskyIndex
+// The Tversky index is an asymmetric similarity that weights the elements only in A by α and
+// those only in B by β. α = β = 1 gives the Jaccard index and α = β = ½ the Dice coefficient.
+// Unless opts say otherwise it is 1 when A & B are both empty and 0 when the denominator is
+// otherwise 0. α and β must not be negative, or the index can leave [0,1]; TverskyIndexErr
+// returns ErrNegativeWeight for them.
+//
+// S(A,B) = |A∩B| / (|A∩B| + α|A−B| + β|B−A|)
+func TverskyIndex(A, B Interface, alpha, beta float64, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return tversky(a, b, c, alpha, beta, opts)
+}
+
+func tversky(a, b, c, alpha, beta float64, opts []SimilarityOption) float64 {
+	den := a + alpha*b + beta*c
+	if den == 0 {
+		return undefined("TverskyIndex", emptyMatch(b, c), opts)
+	}
+	return a / den
+}
+
+// OchiaiCoefficient
+// The Ochiai coefficient is the cosine similarity of the indicator vectors of A & B.
+// Unless opts say otherwise it is 1 when both sets are empty and 0 when only one is.
+//
+// K(A,B) = |A∩B| / √(|A|·|B|)
+func OchiaiCoefficient(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return ochiai(a, b, c, opts)
+}
+
+func ochiai(a, b, c float64, opts []SimilarityOption) float64 {
+	if a+b == 0 || a+c == 0 {
+		return undefined("OchiaiCoefficient", emptyMatch(b, c), opts)
+	}
+	return a / math.Sqrt((a+b)*(a+c))
+}
+
+// KulczynskiSimilarity
+// The (second) Kulczynski similarity is the mean of the fractions of A and of B that are shared.
+// Unless opts say otherwise it is 1 when both sets are empty and 0 when only one is.
+//
+// K(A,B) = ½(|A∩B|/|A| + |A∩B|/|B|)
+func KulczynskiSimilarity(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return kulczynski(a, b, c, opts)
+}
+
+func kulczynski(a, b, c float64, opts []SimilarityOption) float64 {
+	if a+b == 0 || a+c == 0 {
+		return undefined("KulczynskiSimilarity", emptyMatch(b, c), opts)
+	}
+	return (a/(a+b) + a/(a+c)) / 2
+}
+
+// BraunBlanquetSimilarity
+// The Braun-Blanquet similarity is the size of the intersection divided by the size of the larger
+// of the two sets. Unless opts say otherwise it is 1 when both sets are empty.
+//
+// BB(A,B) = |A∩B| / max(|A|,|B|)
+func BraunBlanquetSimilarity(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return braunBlanquet(a, b, c, opts)
+}
+
+func braunBlanquet(a, b, c float64, opts []SimilarityOption) float64 {
+	max := math.Max(a+b, a+c)
+	if max == 0 {
+		return undefined("BraunBlanquetSimilarity", 1, opts)
+	}
+	return a / max
+}
+
+// SimpsonSimilarity
+// The Simpson similarity is the overlap coefficient with the empty set given a defined value.
+// The empty set is a subset of every set, so unless opts say otherwise the similarity is 1 when
+// either set is empty.
+//
+// S(A,B) = |A∩B| / min(|A|,|B|)
+func SimpsonSimilarity(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return simpson(a, b, c, opts)
+}
+
+func simpson(a, b, c float64, opts []SimilarityOption) float64 {
+	min := math.Min(a+b, a+c)
+	if min == 0 {
+		return undefined("SimpsonSimilarity", 1, opts)
+	}
+	return a / min
+}
+
+// emptyMatch is the similarity of two sets at least one of which is empty: 1 if both are.
+func emptyMatch(b, c float64) float64 {
+	if b == 0 && c == 0 {
+		return 1
+	}
+	return 0
+}
+
+// The measures below also count the elements of a universe U that are in neither set, so that
+// shared absence counts as agreement. A & B must be subsets of U: the counts are wrong
+// otherwise, and the Err variants return ErrNotInUniverse instead. Writing a = |A∩B|,
+// b = |A−B|, c = |B−A| and d = |U−(A∪B)|, each is 1 when U is empty unless opts say otherwise.
+
+// SimpleMatchingCoefficient
+// The simple matching coefficient is the fraction of elements of U on which A & B agree.
+//
+// SMC(A,B) = (a + d) / (a + b + c + d)
+func SimpleMatchingCoefficient(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return simpleMatching(a, b, c, d, opts)
+}
+
+func simpleMatching(a, b, c, d float64, opts []SimilarityOption) float64 {
+	if a+b+c+d == 0 {
+		return undefined("SimpleMatchingCoefficient", 1, opts)
+	}
+	return (a + d) / (a + b + c + d)
+}
+
+// RogersTanimotoSimilarity
+// The Rogers–Tanimoto similarity is the simple matching coefficient with disagreements counted twice.
+//
+// RT(A,B) = (a + d) / (a + d + 2(b + c))
+func RogersTanimotoSimilarity(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return rogersTanimoto(a, b, c, d, opts)
+}
+
+func rogersTanimoto(a, b, c, d float64, opts []SimilarityOption) float64 {
+	if a+b+c+d == 0 {
+		return undefined("RogersTanimotoSimilarity", 1, opts)
+	}
+	return (a + d) / (a + d + 2*(b+c))
+}
+
+// HamannSimilarity
+// The Hamann similarity is the fraction of agreements less the fraction of disagreements, in [-1,1].
+//
+// H(A,B) = ((a + d) − (b + c)) / (a + b + c + d)
+func HamannSimilarity(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return hamann(a, b, c, d, opts)
+}
+
+func hamann(a, b, c, d float64, opts []SimilarityOption) float64 {
+	if a+b+c+d == 0 {
+		return undefined("HamannSimilarity", 1, opts)
+	}
+	return ((a + d) - (b + c)) / (a + b + c + d)
+}
+
+// SokalSneathSimilarity
+// The (second) Sokal–Sneath similarity is the simple matching coefficient with agreements counted twice.
+//
+// SS(A,B) = 2(a + d) / (2(a + d) + b + c)
+func SokalSneathSimilarity(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return sokalSneath(a, b, c, d, opts)
+}
+
+func sokalSneath(a, b, c, d float64, opts []SimilarityOption) float64 {
+	if a+b+c+d == 0 {
+		return undefined("SokalSneathSimilarity", 1, opts)
+	}
+	return 2 * (a + d) / (2*(a+d) + b + c)
+}
+
+// YuleQ
+// Yule's Q is the odds ratio of A & B mapped to [-1,1]: 1 when membership of one set never
+// disagrees with the other's, -1 when it always does. When ad = bc = 0, unless opts say
+// otherwise, it is 1 if A = B, -1 if B = U−A and 0 otherwise.
+//
+// Q(A,B) = (ad − bc) / (ad + bc)
+func YuleQ(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return yule(a, b, c, d, opts)
+}
+
+func yule(a, b, c, d float64, opts []SimilarityOption) float64 {
+	if a*d+b*c == 0 {
+		return undefined("YuleQ", degenerateAssociation(a, b, c, d), opts)
+	}
+	return (a*d - b*c) / (a*d + b*c)
+}
+
+// PhiCoefficient
+// The φ coefficient is the Pearson correlation of the indicator vectors of A & B over U, in
+// [-1,1]. When a marginal is 0, because a set is empty or equal to U, unless opts say otherwise
+// it is 1 if A = B, -1 if B = U−A and 0 otherwise.
+//
+// φ(A,B) = (ad − bc) / √((a + b)(c + d)(a + c)(b + d))
+func PhiCoefficient(U, A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c, d := matchesIn(U, A, B)
+	return phi(a, b, c, d, opts)
+}
+
+func phi(a, b, c, d float64, opts []SimilarityOption) float64 {
+	den := (a + b) * (c + d) * (a + c) * (b + d)
+	if den == 0 {
+		return undefined("PhiCoefficient", degenerateAssociation(a, b, c, d), opts)
+	}
+	return (a*d - b*c) / math.Sqrt(den)
+}
+
+// degenerateAssociation is the value of an association measure whose denominator is 0.
+func degenerateAssociation(a, b, c, d float64) float64 {
+	switch {
+	case b == 0 && c == 0:
+		return 1
+	case a == 0 && d == 0:
+		return -1
+	}
+	return 0
+}
+
+// JaccardSimilarityErr is JaccardSimilarity with an undefined index reported as an error.
+func JaccardSimilarityErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return JaccardSimilarity(A, B, o...) })
+}
+
+// JaccardDistanceErr is JaccardDistance with an undefined index reported as an error.
+func JaccardDistanceErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return JaccardDistance(A, B, o...) })
+}
+
+// DSCErr is DSC with an undefined coefficient reported as an error.
+func DSCErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return DSC(A, B, o...) })
+}
+
+// OverlapCoefficientErr is OverlapCoefficient with an undefined coefficient reported as an error.
+func OverlapCoefficientErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return OverlapCoefficient(A, B, o...) })
+}
+
+// TverskyIndexErr is TverskyIndex with an undefined index reported as an error.
+// ErrNegativeWeight is returned if α or β is negative.
+func TverskyIndexErr(A, B Interface, alpha, beta float64, opts ...SimilarityOption) (float64, error) {
+	if !(alpha >= 0 && beta >= 0) {
+		return math.NaN(), ErrNegativeWeight
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return TverskyIndex(A, B, alpha, beta, o...) })
+}
+
+// OchiaiCoefficientErr is OchiaiCoefficient with an undefined coefficient reported as an error.
+func OchiaiCoefficientErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return OchiaiCoefficient(A, B, o...) })
+}
+
+// KulczynskiSimilarityErr is KulczynskiSimilarity with an undefined similarity reported as an error.
+func KulczynskiSimilarityErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return KulczynskiSimilarity(A, B, o...) })
+}
+
+// BraunBlanquetSimilarityErr is BraunBlanquetSimilarity with an undefined similarity reported as an error.
+func BraunBlanquetSimilarityErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return BraunBlanquetSimilarity(A, B, o...) })
+}
+
+// SimpsonSimilarityErr is SimpsonSimilarity with an undefined similarity reported as an error.
+func SimpsonSimilarityErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return SimpsonSimilarity(A, B, o...) })
+}
+
+// SimpleMatchingCoefficientErr is SimpleMatchingCoefficient with an undefined coefficient reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func SimpleMatchingCoefficientErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return SimpleMatchingCoefficient(U, A, B, o...) })
+}
+
+// RogersTanimotoSimilarityErr is RogersTanimotoSimilarity with an undefined similarity reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func RogersTanimotoSimilarityErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return RogersTanimotoSimilarity(U, A, B, o...) })
+}
+
+// HamannSimilarityErr is HamannSimilarity with an undefined similarity reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func HamannSimilarityErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return HamannSimilarity(U, A, B, o...) })
+}
+
+// SokalSneathSimilarityErr is SokalSneathSimilarity with an undefined similarity reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func SokalSneathSimilarityErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return SokalSneathSimilarity(U, A, B, o...) })
+}
+
+// YuleQErr is YuleQ with an undefined association reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func YuleQErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return YuleQ(U, A, B, o...) })
+}
+
+// PhiCoefficientErr is PhiCoefficient with an undefined correlation reported as an error.
+// ErrNotInUniverse is returned if A or B is not a subset of U.
+func PhiCoefficientErr(U, A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := inUniverse(U, A, B); err != nil {
+		return math.NaN(), err
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return PhiCoefficient(U, A, B, o...) })
+}
+
+// inUniverse returns ErrNotInUniverse unless A & B are subsets of U.
+func inUniverse(U, A, B Interface) error {
+	if !isSubset(A, U) || !isSubset(B, U) {
+		return ErrNotInUniverse
+	}
+	return nil
 }
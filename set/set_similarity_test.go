@@ -1,6 +1,7 @@
 package set
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
@@ -40,6 +41,15 @@ func Test_JaccardDistance(t *testing.T) {
 		t.Errorf("expected a distance of 0.6 instead got %.2f", distance)
 		t.Log(index, distance)
 	}
+	if d, err := JaccardDistanceErr(A, B); d != 0.6 || err != nil {
+		t.Errorf("expected a distance of 0.6 and no error instead got %.2f, %v", d, err)
+	}
+	if d, err := JaccardDistanceErr(NewSet(), NewSet()); !math.IsNaN(d) || !errors.Is(err, ErrUndefinedSimilarity) {
+		t.Errorf("expected the distance of two empty sets to be an error instead got %.2f, %v", d, err)
+	}
+	if d, err := JaccardDistanceErr(NewSet(), NewSet(), EmptyAsOne()); d != 0 || err != nil {
+		t.Errorf("expected two empty sets to be 0 apart with EmptyAsOne instead got %.2f, %v", d, err)
+	}
 }
 
 func Test_DSC(t *testing.T) {
@@ -66,3 +76,121 @@ func Test_OverlapCoeffiecient(t *testing.T) {
 		t.Errorf("Expecting 0 but got %f", overlapCo)
 	}
 }
+
+func Test_SimilarityBackends(t *testing.T) {
+	A := NewSet(0, 1, 2, 5, 6, 8, 9)
+	B := NewSet(0, 2, 3, 4, 5, 7, 9)
+	SA := NewSmallSet(A.SetToSlice()...)
+	SB := NewSmallSet(B.SetToSlice()...)
+	pairs := [][2]Interface{{A, B}, {SA, SB}, {A, SB}, {SA, B}}
+	for _, p := range pairs {
+		if index := JaccardSimilarity(p[0], p[1]); index != 0.4 {
+			t.Errorf("expected a similarity index of 0.4 instead got %.2f", index)
+		}
+		if dsc := DSC(p[0], p[1]); math.Abs(dsc-4.0/7) > 1e-9 {
+			t.Errorf("expected a DSC of 4/7 instead got %f", dsc)
+		}
+		if overlap := OverlapCoefficient(p[0], p[1]); math.Abs(overlap-4.0/7) > 1e-9 {
+			t.Errorf("expected an overlap coefficient of 4/7 instead got %f", overlap)
+		}
+	}
+}
+
+func Test_SimilarityCatalogue(t *testing.T) {
+	U := intSet(0, 10)
+	A := NewSet(0, 1, 2, 3, 4)
+	B := NewSet(3, 4, 5)
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Tversky α=β=1", TverskyIndex(A, B, 1, 1), JaccardSimilarity(A, B)},
+		{"Tversky α=β=½", TverskyIndex(A, B, 0.5, 0.5), DSC(A, B)},
+		{"Tversky α=1 β=0", TverskyIndex(A, B, 1, 0), 2.0 / 5},
+		{"Ochiai", OchiaiCoefficient(A, B), 2 / math.Sqrt(15)},
+		{"Kulczynski", KulczynskiSimilarity(A, B), 8.0 / 15},
+		{"Braun-Blanquet", BraunBlanquetSimilarity(A, B), 2.0 / 5},
+		{"Simpson", SimpsonSimilarity(A, B), OverlapCoefficient(A, B)},
+		{"Simple matching", SimpleMatchingCoefficient(U, A, B), 0.6},
+		{"Rogers-Tanimoto", RogersTanimotoSimilarity(U, A, B), 3.0 / 7},
+		{"Hamann", HamannSimilarity(U, A, B), 0.2},
+		{"Sokal-Sneath", SokalSneathSimilarity(U, A, B), 0.75},
+		{"Yule", YuleQ(U, A, B), 5.0 / 11},
+		{"Phi", PhiCoefficient(U, A, B), 1 / math.Sqrt(21)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func Test_SimilarityCatalogueEmptySets(t *testing.T) {
+	E := NewSet()
+	A := NewSet(1, 2)
+	U := NewSet(1, 2, 3)
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Tversky ∅,∅", TverskyIndex(E, E, 1, 1), 1},
+		{"Tversky ∅,A", TverskyIndex(E, A, 0, 0), 0},
+		{"Ochiai ∅,∅", OchiaiCoefficient(E, E), 1},
+		{"Ochiai ∅,A", OchiaiCoefficient(E, A), 0},
+		{"Kulczynski ∅,∅", KulczynskiSimilarity(E, E), 1},
+		{"Kulczynski A,∅", KulczynskiSimilarity(A, E), 0},
+		{"Braun-Blanquet ∅,∅", BraunBlanquetSimilarity(E, E), 1},
+		{"Braun-Blanquet ∅,A", BraunBlanquetSimilarity(E, A), 0},
+		{"Simpson ∅,∅", SimpsonSimilarity(E, E), 1},
+		{"Simpson ∅,A", SimpsonSimilarity(E, A), 1},
+		{"Simple matching U=∅", SimpleMatchingCoefficient(E, E, E), 1},
+		{"Rogers-Tanimoto U=∅", RogersTanimotoSimilarity(E, E, E), 1},
+		{"Hamann U=∅", HamannSimilarity(E, E, E), 1},
+		{"Sokal-Sneath U=∅", SokalSneathSimilarity(E, E, E), 1},
+		{"Yule A=B", YuleQ(U, A, A), 1},
+		{"Yule B=U−A", YuleQ(U, A, NewSet(3)), -1},
+		{"Yule ∅,A", YuleQ(U, E, A), 0},
+		{"Phi A=B=U", PhiCoefficient(U, U, U), 1},
+		{"Phi ∅,U", PhiCoefficient(U, E, U), -1},
+		{"Phi ∅,A", PhiCoefficient(U, E, A), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func Test_SimilarityPreconditions(t *testing.T) {
+	A := NewSet(0, 1, 2, 3, 4)
+	B := NewSet(3, 4, 5)
+	for _, w := range [][2]float64{{-1, 1}, {1, -0.5}, {math.NaN(), 1}} {
+		if _, err := TverskyIndexErr(A, B, w[0], w[1]); err != ErrNegativeWeight {
+			t.Errorf("Tversky α=%f β=%f: expecting ErrNegativeWeight instead got %v", w[0], w[1], err)
+		}
+	}
+	if s, err := TverskyIndexErr(A, B, 1, 0); s != 2.0/5 || err != nil {
+		t.Errorf("Expecting a Tversky index of 0.4 instead got %f, %v", s, err)
+	}
+	U := intSet(0, 5) // lacks 5, which is in B
+	for name, f := range map[string]func(U, A, B Interface, opts ...SimilarityOption) (float64, error){
+		"SimpleMatchingCoefficient": SimpleMatchingCoefficientErr,
+		"RogersTanimotoSimilarity":  RogersTanimotoSimilarityErr,
+		"HamannSimilarity":          HamannSimilarityErr,
+		"SokalSneathSimilarity":     SokalSneathSimilarityErr,
+		"YuleQ":                     YuleQErr,
+		"PhiCoefficient":            PhiCoefficientErr,
+	} {
+		if _, err := f(U, A, B); err != ErrNotInUniverse {
+			t.Errorf("%s(U, A, B): expecting ErrNotInUniverse instead got %v", name, err)
+		}
+		if _, err := f(U, B, A); err != ErrNotInUniverse {
+			t.Errorf("%s(U, B, A): expecting ErrNotInUniverse instead got %v", name, err)
+		}
+		if _, err := f(intSet(0, 10), A, B); err != nil {
+			t.Errorf("%s: expecting no error for subsets of U instead got %v", name, err)
+		}
+	}
+}
@@ -328,7 +328,7 @@ func Test_Complement(t *testing.T) {
 	A := NewSet(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15)
 	B := NewSet(1, 2, 3, 4, 5)
 	C := NewSet(6, 7, 8, 9, 10)
-	U := []*Set{B, C}
+	U := []Interface{B, C}
 	D := Complement(A, U...)
 	if D.Cardinality() != 5 {
 		t.Errorf("Expecting cardinality of the complement to be 5 instead got %f", D.Cardinality())
@@ -360,3 +360,42 @@ func Test_DisjointUnion(t *testing.T) {
 	C := DisjointUnion(A, B)
 	fmt.Println(C.String())
 }
+
+func Test_InterfaceBackends(t *testing.T) {
+	var _ Interface = NewSet()
+	var _ Interface = NewSmallSet()
+	A := NewSet(1, 2, 3, 4)
+	B := NewSmallSet(3, 4, 5)
+	if C := Union(A, B); !C.IsEqual(NewSet(1, 2, 3, 4, 5)) {
+		t.Errorf("Expecting a union of {1, 2, 3, 4, 5} instead got %v", C)
+	}
+	if C := Intersect(B, A); !C.IsEqual(NewSmallSet(3, 4)) {
+		t.Errorf("Expecting an intersection of {3, 4} instead got %v", C)
+	}
+	if C := Difference(A, B); !C.IsEqual(NewSet(1, 2)) {
+		t.Errorf("Expecting a difference of {1, 2} instead got %v", C)
+	}
+	if C := SymetricDifferencec(A, B); !Equals(C, NewSmallSet(1, 2, 5)) {
+		t.Errorf("Expecting a symmetric difference of {1, 2, 5} instead got %v", C)
+	}
+	if !A.IsSuperset(NewSmallSet(1, 2)) || A.IsSubset(B) || A.IsDisjoint(B) {
+		t.Error("Expecting subset and disjointness checks to work across backends")
+	}
+	if C := Complement(A, B, NewSmallSet(1)); !C.IsEqual(NewSet(2)) {
+		t.Errorf("Expecting a complement of {2} instead got %v", C)
+	}
+	if C := DisjointUnion(A, B); C.Len() != 7 || !C.Contains(Tuple{5, 1}) {
+		t.Errorf("Expecting a disjoint union of 7 tagged elements instead got %v", C)
+	}
+	if C := CartesianProduct(B, A); C.Len() != 12 {
+		t.Errorf("Expecting a cartesian product of 12 pairs instead got %d", C.Len())
+	}
+	n := 0
+	A.Iterate(func(e interface{}) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("Expecting Iterate to stop when fn returns false, visited %d elements", n)
+	}
+}
@@ -0,0 +1,208 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrUnsupportedMeasure is returned when a function does not support the similarity measure asked for.
+var ErrUnsupportedMeasure = errors.New("set: unsupported similarity measure")
+
+// joinBounds gives, for a measure and threshold t, the overlap two sets of sizes x & y need to
+// reach t, and the least overlap a set of size x needs with a set of any size.
+type joinBounds struct {
+	m Measure
+	t float64
+}
+
+// ceil rounds up, allowing for the rounding error of the bounds so that no match is missed.
+func ceil(v float64) int {
+	return int(math.Ceil(v - 1e-9))
+}
+
+func (j joinBounds) required(x, y int) int {
+	fx, fy := float64(x), float64(y)
+	switch j.m.kind {
+	case jaccardMeasure:
+		return ceil(j.t / (1 + j.t) * (fx + fy))
+	case diceMeasure:
+		return ceil(j.t / 2 * (fx + fy))
+	case ochiaiMeasure:
+		return ceil(j.t * math.Sqrt(fx*fy))
+	default:
+		return ceil(j.t * math.Min(fx, fy))
+	}
+}
+
+func (j joinBounds) least(x int) int {
+	fx := float64(x)
+	switch j.m.kind {
+	case jaccardMeasure:
+		return ceil(j.t * fx)
+	case diceMeasure:
+		return ceil(j.t * fx / (2 - j.t))
+	case ochiaiMeasure:
+		return ceil(j.t * j.t * fx)
+	default:
+		return 1
+	}
+}
+
+// prefix returns the number of leading elements of a set of size x that any set matching it
+// must share at least one of.
+func (j joinBounds) prefix(x int) int {
+	if p := x - j.least(x) + 1; p < x {
+		return p
+	}
+	return x
+}
+
+// SimilarityJoin returns every pair of a set in R and a set in S whose similarity under m is at
+// least t. It gives the same result as comparing every pair but, like the PPJoin algorithm,
+// only compares sets that can reach t:
+//
+//   - elements are ordered from rarest to most common, and two sets can only reach t if they
+//     share one of the first few elements of each (prefix filtering),
+//   - sets whose sizes are too different to reach t are skipped (length filtering),
+//   - pairs whose shared prefix elements are too far into either set to leave room for enough
+//     overlap are dropped (positional filtering).
+//
+// Jaccard, Dice, Overlap and Ochiai (cosine) are supported. Overlap gains nothing from length or
+// prefix filtering, as a small set can match a large one with a single element in common.
+// Empty sets never match. The result maps Tuples of the names of the sets in R & S to their
+// similarity.
+// ErrInvalidThreshold is returned unless 0 < t ≤ 1 and ErrUnsupportedMeasure for other measures.
+func SimilarityJoin(R, S map[string]Interface, m Measure, t float64) (map[Tuple]float64, error) {
+	if !(t > 0 && t <= 1) {
+		return nil, ErrInvalidThreshold
+	}
+	if k := m.kind; k != jaccardMeasure && k != diceMeasure && k != overlapMeasure && k != ochiaiMeasure {
+		return nil, ErrUnsupportedMeasure
+	}
+	bounds := joinBounds{m, t}
+	rank := joinOrder(R, S)
+	r := toRanks(R, rank)
+	s := toRanks(S, rank)
+	type posting struct{ set, pos int }
+	index := make(map[int][]posting)
+	for i, ranks := range s.sets {
+		for pos, e := range ranks[:bounds.prefix(len(ranks))] {
+			index[e] = append(index[e], posting{i, pos})
+		}
+	}
+	matches := make(map[Tuple]float64)
+	for i, x := range r.sets {
+		// overlap counts the prefix elements shared with each candidate, or -1 once it is pruned.
+		overlap := make(map[int]int)
+		for pos, e := range x[:bounds.prefix(len(x))] {
+			for _, p := range index[e] {
+				y := s.sets[p.set]
+				if overlap[p.set] < 0 {
+					continue
+				}
+				need := bounds.required(len(x), len(y))
+				if need > len(x) || need > len(y) {
+					overlap[p.set] = -1
+					continue
+				}
+				rest := len(x) - pos - 1
+				if len(y)-p.pos-1 < rest {
+					rest = len(y) - p.pos - 1
+				}
+				if overlap[p.set]+1+rest < need {
+					overlap[p.set] = -1
+					continue
+				}
+				overlap[p.set]++
+			}
+		}
+		for j, n := range overlap {
+			if n < 0 {
+				continue
+			}
+			y := s.sets[j]
+			a := float64(sortedOverlap(x, y))
+			score := m.fromCounts(a, float64(len(x))-a, float64(len(y))-a, nil)
+			if score >= t {
+				matches[NewTuple(r.names[i], s.names[j])] = score
+			}
+		}
+	}
+	return matches, nil
+}
+
+// joinOrder ranks every element of R & S from rarest to most common.
+func joinOrder(R, S map[string]Interface) map[interface{}]int {
+	freq := make(map[interface{}]int)
+	for _, c := range []map[string]Interface{R, S} {
+		for _, A := range c {
+			A.Iterate(func(e interface{}) bool {
+				freq[e]++
+				return true
+			})
+		}
+	}
+	type entry struct {
+		e    interface{}
+		f    int
+		hash uint64
+	}
+	entries := make([]entry, 0, len(freq))
+	for e, f := range freq {
+		entries = append(entries, entry{e, f, hashElement(e, 0)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].f != entries[j].f {
+			return entries[i].f < entries[j].f
+		}
+		return entries[i].hash < entries[j].hash
+	})
+	rank := make(map[interface{}]int, len(entries))
+	for i, en := range entries {
+		rank[en.e] = i
+	}
+	return rank
+}
+
+// rankedSets holds named sets as sorted slices of element ranks.
+type rankedSets struct {
+	names []string
+	sets  [][]int
+}
+
+func toRanks(C map[string]Interface, rank map[interface{}]int) rankedSets {
+	r := rankedSets{names: make([]string, 0, len(C))}
+	for n := range C {
+		r.names = append(r.names, n)
+	}
+	sort.Strings(r.names)
+	r.sets = make([][]int, len(r.names))
+	for i, n := range r.names {
+		ranks := make([]int, 0, C[n].Len())
+		C[n].Iterate(func(e interface{}) bool {
+			ranks = append(ranks, rank[e])
+			return true
+		})
+		sort.Ints(ranks)
+		r.sets[i] = ranks
+	}
+	return r
+}
+
+// sortedOverlap returns the number of values in both of the sorted slices x & y.
+func sortedOverlap(x, y []int) (n int) {
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		switch {
+		case x[i] < y[j]:
+			i++
+		case x[i] > y[j]:
+			j++
+		default:
+			n++
+			i++
+			j++
+		}
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package set
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// randomCollection returns n sets of varied sizes over a skewed universe, some of them near
+// copies of earlier ones.
+func randomCollection(r *rand.Rand, prefix string, n int) map[string]Interface {
+	C := make(map[string]Interface)
+	var previous *Set
+	for i := 0; i < n; i++ {
+		S := NewSet()
+		if previous != nil && r.Intn(3) == 0 {
+			for e := range previous.E {
+				if r.Intn(10) > 0 {
+					S.Add(e)
+				}
+			}
+		}
+		for size := 1 + r.Intn(25); S.Len() < size; {
+			S.Add(r.Intn(1 + r.Intn(200)))
+		}
+		previous = S
+		C[fmt.Sprintf("%s%d", prefix, i)] = S
+	}
+	C[prefix+"empty"] = NewSet()
+	return C
+}
+
+func Test_SimilarityJoin(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	R := randomCollection(r, "r", 150)
+	S := randomCollection(r, "s", 150)
+	for i := 0; i < 40; i++ {
+		copied := NewSet()
+		for e := range R[fmt.Sprintf("r%d", i)].(*Set).E {
+			if r.Intn(8) > 0 {
+				copied.Add(e)
+			}
+		}
+		copied.Add(r.Intn(200))
+		S[fmt.Sprintf("copy%d", i)] = copied
+	}
+	S["small"] = NewSmallSet(3, 5, 8)
+	for _, m := range []Measure{Jaccard, Dice, Overlap, Ochiai} {
+		for _, threshold := range []float64{0.3, 0.5, 0.8, 1} {
+			got, err := SimilarityJoin(R, S, m, threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 0
+			for rn, A := range R {
+				for sn, B := range S {
+					score := m.Similarity(A, B)
+					if A.Len() == 0 || B.Len() == 0 || !(score >= threshold) {
+						continue
+					}
+					want++
+					if s, ok := got[NewTuple(rn, sn)]; !ok || s != score {
+						t.Errorf("%v ≥ %.1f: expecting (%s, %s) with %f instead got %f, %v", m, threshold, rn, sn, score, s, ok)
+					}
+				}
+			}
+			if len(got) != want {
+				t.Errorf("%v ≥ %.1f: expecting %d pairs instead got %d", m, threshold, want, len(got))
+			}
+		}
+	}
+}
+
+func Test_SimilarityJoinErrors(t *testing.T) {
+	C := map[string]Interface{"a": NewSet(1)}
+	if _, err := SimilarityJoin(C, C, Jaccard, 0); err != ErrInvalidThreshold {
+		t.Errorf("Expecting ErrInvalidThreshold instead got %v", err)
+	}
+	if _, err := SimilarityJoin(C, C, Kulczynski, 0.5); err != ErrUnsupportedMeasure {
+		t.Errorf("Expecting ErrUnsupportedMeasure instead got %v", err)
+	}
+	got, _ := SimilarityJoin(C, C, Jaccard, 1)
+	if s, ok := got[NewTuple("a", "a")]; !ok || s != 1 || len(got) != 1 {
+		t.Errorf("Expecting a self-join to match a with itself instead got %v", got)
+	}
+}
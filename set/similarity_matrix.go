@@ -0,0 +1,343 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Measure is a similarity measure of two sets that depends only on the number of elements in
+// both, in one only and, for the measures over a universe, in neither, which lets it be
+// computed for many pairs of sets at once from the sizes of their intersections. Measures are
+// the variables below and the values of Tversky and of the functions taking a universe; the
+// zero Measure is not one.
+type Measure struct {
+	kind        measureKind
+	alpha, beta float64   // the weights of Tversky
+	universe    Interface // the universe of the measures counting shared absences
+}
+
+type measureKind int
+
+const (
+	noMeasure measureKind = iota
+	jaccardMeasure
+	diceMeasure
+	overlapMeasure
+	ochiaiMeasure
+	kulczynskiMeasure
+	braunBlanquetMeasure
+	simpsonMeasure
+	tverskyMeasure
+	simpleMatchingMeasure
+	rogersTanimotoMeasure
+	hamannMeasure
+	sokalSneathMeasure
+	yuleMeasure
+	phiMeasure
+)
+
+// The measures of set_similarity.go that depend only on |A|, |B| and |A∩B|.
+var (
+	Jaccard       = Measure{kind: jaccardMeasure}       // JaccardSimilarity
+	Dice          = Measure{kind: diceMeasure}          // DSC
+	Overlap       = Measure{kind: overlapMeasure}       // OverlapCoefficient
+	Ochiai        = Measure{kind: ochiaiMeasure}        // OchiaiCoefficient
+	Kulczynski    = Measure{kind: kulczynskiMeasure}    // KulczynskiSimilarity
+	BraunBlanquet = Measure{kind: braunBlanquetMeasure} // BraunBlanquetSimilarity
+	Simpson       = Measure{kind: simpsonMeasure}       // SimpsonSimilarity
+)
+
+// Tversky returns the TverskyIndex with weights alpha & beta. ErrNegativeWeight is returned
+// when it is used with a negative weight.
+func Tversky(alpha, beta float64) Measure {
+	return Measure{kind: tverskyMeasure, alpha: alpha, beta: beta}
+}
+
+// The measures of set_similarity.go that also count the elements of a universe U in neither
+// set. ErrNotInUniverse is returned when they are used with sets that are not subsets of U.
+
+// SimpleMatching returns the SimpleMatchingCoefficient over U.
+func SimpleMatching(U Interface) Measure {
+	return Measure{kind: simpleMatchingMeasure, universe: U}
+}
+
+// RogersTanimoto returns the RogersTanimotoSimilarity over U.
+func RogersTanimoto(U Interface) Measure {
+	return Measure{kind: rogersTanimotoMeasure, universe: U}
+}
+
+// Hamann returns the HamannSimilarity over U.
+func Hamann(U Interface) Measure {
+	return Measure{kind: hamannMeasure, universe: U}
+}
+
+// SokalSneath returns the SokalSneathSimilarity over U.
+func SokalSneath(U Interface) Measure {
+	return Measure{kind: sokalSneathMeasure, universe: U}
+}
+
+// Yule returns YuleQ over U.
+func Yule(U Interface) Measure {
+	return Measure{kind: yuleMeasure, universe: U}
+}
+
+// Phi returns the PhiCoefficient over U.
+func Phi(U Interface) Measure {
+	return Measure{kind: phiMeasure, universe: U}
+}
+
+func (m Measure) String() string {
+	switch m.kind {
+	case jaccardMeasure:
+		return "Jaccard"
+	case diceMeasure:
+		return "Dice"
+	case overlapMeasure:
+		return "Overlap"
+	case ochiaiMeasure:
+		return "Ochiai"
+	case kulczynskiMeasure:
+		return "Kulczynski"
+	case braunBlanquetMeasure:
+		return "BraunBlanquet"
+	case simpsonMeasure:
+		return "Simpson"
+	case tverskyMeasure:
+		return fmt.Sprintf("Tversky(%g, %g)", m.alpha, m.beta)
+	case simpleMatchingMeasure:
+		return "SimpleMatching"
+	case rogersTanimotoMeasure:
+		return "RogersTanimoto"
+	case hamannMeasure:
+		return "Hamann"
+	case sokalSneathMeasure:
+		return "SokalSneath"
+	case yuleMeasure:
+		return "Yule"
+	case phiMeasure:
+		return "Phi"
+	}
+	return "Measure(?)"
+}
+
+// overUniverse checks if m counts the elements of a universe in neither set.
+func (m Measure) overUniverse() bool {
+	return m.kind >= simpleMatchingMeasure && m.kind <= phiMeasure
+}
+
+// check returns ErrUnsupportedMeasure if m is not a measure, as the zero Measure or one over a
+// nil universe, and ErrNegativeWeight for Tversky with a negative weight.
+func (m Measure) check() error {
+	switch {
+	case m.kind <= noMeasure || m.kind > phiMeasure, m.overUniverse() && m.universe == nil:
+		return ErrUnsupportedMeasure
+	case m.kind == tverskyMeasure && !(m.alpha >= 0 && m.beta >= 0):
+		return ErrNegativeWeight
+	}
+	return nil
+}
+
+// Similarity returns the similarity of A & B under m. It panics if m is not a measure, and the
+// measures over a universe count wrongly unless A & B are subsets of it.
+func (m Measure) Similarity(A, B Interface, opts ...SimilarityOption) float64 {
+	a, b, c := matches(A, B)
+	return m.fromCounts(a, b, c, opts)
+}
+
+// SimilarityErr is Similarity with an undefined similarity reported as an error.
+// ErrUnsupportedMeasure or ErrNegativeWeight is returned if m is not a valid measure, and
+// ErrNotInUniverse if it is over a universe that A or B is not a subset of.
+func (m Measure) SimilarityErr(A, B Interface, opts ...SimilarityOption) (float64, error) {
+	if err := m.check(); err != nil {
+		return math.NaN(), err
+	}
+	if m.overUniverse() {
+		if err := inUniverse(m.universe, A, B); err != nil {
+			return math.NaN(), err
+		}
+	}
+	return checked(opts, func(o ...SimilarityOption) float64 { return m.Similarity(A, B, o...) })
+}
+
+// fromCounts returns the similarity of sets with a elements in common, b only in the first and
+// c only in the second.
+func (m Measure) fromCounts(a, b, c float64, opts []SimilarityOption) float64 {
+	switch m.kind {
+	case jaccardMeasure:
+		return jaccard(a, b, c, opts)
+	case diceMeasure:
+		return dice(a, b, c, opts)
+	case overlapMeasure:
+		return overlap(a, b, c, opts)
+	case ochiaiMeasure:
+		return ochiai(a, b, c, opts)
+	case kulczynskiMeasure:
+		return kulczynski(a, b, c, opts)
+	case braunBlanquetMeasure:
+		return braunBlanquet(a, b, c, opts)
+	case simpsonMeasure:
+		return simpson(a, b, c, opts)
+	case tverskyMeasure:
+		return tversky(a, b, c, m.alpha, m.beta, opts)
+	}
+	if !m.overUniverse() || m.universe == nil {
+		panic("set: unknown similarity measure")
+	}
+	d := float64(m.universe.Len()) - a - b - c
+	switch m.kind {
+	case simpleMatchingMeasure:
+		return simpleMatching(a, b, c, d, opts)
+	case rogersTanimotoMeasure:
+		return rogersTanimoto(a, b, c, d, opts)
+	case hamannMeasure:
+		return hamann(a, b, c, d, opts)
+	case sokalSneathMeasure:
+		return sokalSneath(a, b, c, d, opts)
+	case yuleMeasure:
+		return yule(a, b, c, d, opts)
+	}
+	return phi(a, b, c, d, opts)
+}
+
+// Matrix is a matrix of the similarities or distances between named sets, with the value of
+// the set of row i against the set of column j at (i, j). It is symmetric unless the measure
+// is not, like Tversky with α ≠ β. Rows and columns are in the order of Names.
+type Matrix struct {
+	names  []string
+	index  map[string]int
+	values [][]float64
+}
+
+func newMatrix(names []string) *Matrix {
+	M := &Matrix{names: names, index: make(map[string]int, len(names)), values: make([][]float64, len(names))}
+	for i, n := range names {
+		M.index[n] = i
+		M.values[i] = make([]float64, len(names))
+	}
+	return M
+}
+
+// Names returns the names of the rows and columns of M, in sorted order.
+func (M *Matrix) Names() []string {
+	return append([]string(nil), M.names...)
+}
+
+// Len returns the number of rows of M.
+func (M *Matrix) Len() int {
+	return len(M.names)
+}
+
+// At returns the value in row i and column j of M.
+func (M *Matrix) At(i, j int) float64 {
+	return M.values[i][j]
+}
+
+// Get returns the value of M for the sets named a & b, and false if either is not in M.
+func (M *Matrix) Get(a, b string) (float64, bool) {
+	i, ok := M.index[a]
+	if !ok {
+		return 0, false
+	}
+	j, ok := M.index[b]
+	if !ok {
+		return 0, false
+	}
+	return M.values[i][j], true
+}
+
+// PairwiseSimilarity returns the matrix of the similarities under m of every pair of sets in
+// sets. Rows are computed in parallel. An inverted index from elements to the sets holding them
+// counts every intersection in one pass over the elements of each set, so the cost depends on
+// how much the sets overlap rather than on the number of pairs times their sizes.
+// ErrUnsupportedMeasure or ErrNegativeWeight is returned if m is not a valid measure,
+// ErrNotInUniverse if it is over a universe that a set is not a subset of, and an
+// *UndefinedSimilarityError if the similarity of any pair, such as two empty sets, is undefined
+// and opts choose no value for it.
+func PairwiseSimilarity(sets map[string]Interface, m Measure, opts ...SimilarityOption) (*Matrix, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(sets))
+	for n, S := range sets {
+		if m.overUniverse() && !isSubset(S, m.universe) {
+			return nil, ErrNotInUniverse
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	M := newMatrix(names)
+	sizes := make([]float64, len(names))
+	elements := make([][]interface{}, len(names))
+	inverted := make(map[interface{}][]int)
+	for i, n := range names {
+		elements[i] = members(sets[n])
+		sizes[i] = float64(len(elements[i]))
+		for _, e := range elements[i] {
+			inverted[e] = append(inverted[e], i)
+		}
+	}
+	// Workers report undefined similarities to errors of their own.
+	errs := make([]error, runtime.GOMAXPROCS(0))
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := range errs {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			opts := append(opts[:len(opts):len(opts)], reportUndefined(&errs[w]))
+			common := make([]float64, len(names))
+			for i := range rows {
+				for j := range common {
+					common[j] = 0
+				}
+				// Only sets after i are counted, so each cell is written by a single worker.
+				for _, e := range elements[i] {
+					for _, j := range inverted[e] {
+						if j > i {
+							common[j]++
+						}
+					}
+				}
+				M.values[i][i] = m.fromCounts(sizes[i], 0, 0, opts)
+				for j := i + 1; j < len(names); j++ {
+					a := common[j]
+					s := m.fromCounts(a, sizes[i]-a, sizes[j]-a, opts)
+					M.values[i][j], M.values[j][i] = s, s
+					if m.kind == tverskyMeasure && m.alpha != m.beta {
+						M.values[j][i] = m.fromCounts(a, sizes[j]-a, sizes[i]-a, opts)
+					}
+				}
+			}
+		}(w)
+	}
+	for i := range names {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return M, nil
+}
+
+// PairwiseDistance returns the matrix of the distances 1 - s under m of every pair of sets in
+// sets, such as the JaccardDistance for Jaccard.
+// Errors are returned as for PairwiseSimilarity, so the distances are never NaN.
+func PairwiseDistance(sets map[string]Interface, m Measure, opts ...SimilarityOption) (*Matrix, error) {
+	M, err := PairwiseSimilarity(sets, m, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range M.values {
+		for j := range row {
+			row[j] = 1 - row[j]
+		}
+	}
+	return M, nil
+}
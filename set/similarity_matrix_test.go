@@ -0,0 +1,188 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_PairwiseSimilarity(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	sets := map[string]Interface{"empty": NewSet()}
+	for _, n := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		S := NewSet()
+		for i := 0; i < 30; i++ {
+			S.Add(r.Intn(60))
+		}
+		sets[n] = S
+	}
+	U := intSet(0, 60)
+	over := func(f func(U, A, B Interface, opts ...SimilarityOption) float64) func(A, B Interface, opts ...SimilarityOption) float64 {
+		return func(A, B Interface, opts ...SimilarityOption) float64 { return f(U, A, B, opts...) }
+	}
+	direct := map[Measure]func(A, B Interface, opts ...SimilarityOption) float64{
+		Jaccard:       JaccardSimilarity,
+		Dice:          DSC,
+		Overlap:       OverlapCoefficient,
+		Ochiai:        OchiaiCoefficient,
+		Kulczynski:    KulczynskiSimilarity,
+		BraunBlanquet: BraunBlanquetSimilarity,
+		Simpson:       SimpsonSimilarity,
+		Tversky(1, 0.5): func(A, B Interface, opts ...SimilarityOption) float64 {
+			return TverskyIndex(A, B, 1, 0.5, opts...)
+		},
+		SimpleMatching(U): over(SimpleMatchingCoefficient),
+		RogersTanimoto(U): over(RogersTanimotoSimilarity),
+		Hamann(U):         over(HamannSimilarity),
+		SokalSneath(U):    over(SokalSneathSimilarity),
+		Yule(U):           over(YuleQ),
+		Phi(U):            over(PhiCoefficient),
+	}
+	for m, fn := range direct {
+		M, err := PairwiseSimilarity(sets, m, EmptyAsZero())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if M.Len() != len(sets) || M.Names()[0] != "a" {
+			t.Fatalf("Expecting %d sorted names instead got %v", len(sets), M.Names())
+		}
+		for _, a := range M.Names() {
+			for _, b := range M.Names() {
+				got, _ := M.Get(a, b)
+				if want := fn(sets[a], sets[b], EmptyAsZero()); math.Abs(got-want) > 1e-12 {
+					t.Errorf("%v(%s, %s): expecting %f instead got %f", m, a, b, want, got)
+				}
+			}
+		}
+	}
+	D, err := PairwiseDistance(sets, Jaccard, EmptyAsOne())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := D.Get("a", "b"); d != JaccardDistance(sets["a"], sets["b"]) {
+		t.Errorf("Expecting the Jaccard distance instead got %f", d)
+	}
+	if _, ok := D.Get("a", "z"); ok {
+		t.Error("Expecting no value for an unknown name")
+	}
+	if _, err := PairwiseSimilarity(sets, Jaccard); !errors.Is(err, ErrUndefinedSimilarity) {
+		t.Errorf("Expecting an error for the similarity of the empty set with itself instead got %v", err)
+	}
+	for _, m := range []Measure{{}, SimpleMatching(nil)} {
+		if _, err := PairwiseSimilarity(sets, m, EmptyAsZero()); err != ErrUnsupportedMeasure {
+			t.Errorf("%v: expecting ErrUnsupportedMeasure instead got %v", m, err)
+		}
+	}
+	if _, err := PairwiseSimilarity(sets, Tversky(-1, 1), EmptyAsZero()); err != ErrNegativeWeight {
+		t.Errorf("Expecting ErrNegativeWeight instead got %v", err)
+	}
+	if _, err := PairwiseSimilarity(sets, Phi(intSet(0, 30)), EmptyAsZero()); err != ErrNotInUniverse {
+		t.Errorf("Expecting ErrNotInUniverse instead got %v", err)
+	}
+	if _, err := (Measure{}).SimilarityErr(sets["a"], sets["b"]); err != ErrUnsupportedMeasure {
+		t.Errorf("Expecting ErrUnsupportedMeasure from SimilarityErr instead got %v", err)
+	}
+	if _, err := Phi(intSet(0, 30)).SimilarityErr(sets["a"], sets["b"]); err != ErrNotInUniverse {
+		t.Errorf("Expecting ErrNotInUniverse from SimilarityErr instead got %v", err)
+	}
+}
+
+// clusteredSets returns sets in three groups, each of noisy copies of a base set.
+func clusteredSets() (map[string]Interface, *Set) {
+	r := rand.New(rand.NewSource(11))
+	sets := make(map[string]Interface)
+	want := NewSet()
+	for g := 0; g < 3; g++ {
+		names := NewSet()
+		for c := 0; c < 5+g; c++ {
+			S := NewSet()
+			for i := 0; i < 40; i++ {
+				if r.Float64() < 0.8 {
+					S.Add(g*100 + i)
+				}
+			}
+			for i := 0; i < 5; i++ {
+				S.Add(1000 + r.Intn(1000))
+			}
+			name := string(rune('a'+g)) + string(rune('0'+c))
+			sets[name] = S
+			names.Add(name)
+		}
+		want.Add(names)
+	}
+	return sets, want
+}
+
+// sameClusters checks if two sets of sets of names hold the same clusters.
+func sameClusters(A, B *Set) bool {
+	if A.Len() != B.Len() {
+		return false
+	}
+	for a := range A.E {
+		found := false
+		for b := range B.E {
+			if a.(*Set).IsEqual(b.(*Set)) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_Hierarchical(t *testing.T) {
+	sets, want := clusteredSets()
+	D, err := PairwiseDistance(sets, Jaccard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, linkage := range []Linkage{SingleLinkage, CompleteLinkage, AverageLinkage} {
+		C, err := D.Hierarchical(3, linkage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameClusters(C, want) {
+			t.Errorf("Linkage %d: expecting %v instead got %v", linkage, want, C)
+		}
+	}
+	if C, _ := D.Hierarchical(1, SingleLinkage); C.Len() != 1 {
+		t.Errorf("Expecting a single cluster instead got %d", C.Len())
+	}
+	if C, _ := D.Hierarchical(D.Len(), SingleLinkage); C.Len() != D.Len() {
+		t.Errorf("Expecting a cluster per set instead got %d", C.Len())
+	}
+	if _, err := D.Hierarchical(0, SingleLinkage); err != ErrInvalidClusterCount {
+		t.Errorf("Expecting ErrInvalidClusterCount instead got %v", err)
+	}
+	D.values[0][1] = math.NaN()
+	if _, err := D.Hierarchical(3, SingleLinkage); err != ErrInvalidDistance {
+		t.Errorf("Expecting ErrInvalidDistance instead got %v", err)
+	}
+	if _, _, err := D.KMedoids(3); err != ErrInvalidDistance {
+		t.Errorf("Expecting ErrInvalidDistance instead got %v", err)
+	}
+}
+
+func Test_KMedoids(t *testing.T) {
+	sets, want := clusteredSets()
+	D, err := PairwiseDistance(sets, Jaccard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	C, medoids, err := D.KMedoids(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameClusters(C, want) {
+		t.Errorf("Expecting %v instead got %v", want, C)
+	}
+	if len(medoids) != 3 || medoids[0][0] == medoids[1][0] || medoids[1][0] == medoids[2][0] || medoids[0][0] == medoids[2][0] {
+		t.Errorf("Expecting a medoid from each group instead got %v", medoids)
+	}
+	if _, _, err := D.KMedoids(D.Len() + 1); err != ErrInvalidClusterCount {
+		t.Errorf("Expecting ErrInvalidClusterCount instead got %v", err)
+	}
+}
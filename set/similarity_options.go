@@ -0,0 +1,94 @@
+package set
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrUndefinedSimilarity is matched by every UndefinedSimilarityError.
+var ErrUndefinedSimilarity = errors.New("set: similarity is undefined")
+
+// UndefinedSimilarityError reports a similarity measure that is 0/0 for its operands, such as
+// the Jaccard index of two empty sets. It is returned by the functions of the package that
+// return an error, such as JaccardSimilarityErr.
+type UndefinedSimilarityError struct {
+	Measure string
+}
+
+func (e *UndefinedSimilarityError) Error() string {
+	return "set: " + e.Measure + " is undefined (0/0) for these sets"
+}
+
+// Unwrap lets errors.Is match the error against ErrUndefinedSimilarity.
+func (e *UndefinedSimilarityError) Unwrap() error {
+	return ErrUndefinedSimilarity
+}
+
+// SimilarityOption configures a similarity measure. Every similarity function in this package
+// accepts options after its operands.
+//
+// The options decide what a measure returns when it is undefined, which happens when its
+// formula is 0/0: typically when one or both sets are empty. Without an option each measure
+// returns the value in its documentation, which is NaN for JaccardSimilarity, DSC and
+// OverlapCoefficient and their weighted and fuzzy forms.
+//
+// Every measure has an Err variant, such as JaccardSimilarityErr, that returns NaN and an
+// *UndefinedSimilarityError instead, unless an option chooses a value. So do the other functions
+// that return an error, such as Signature.Jaccard and PairwiseSimilarity.
+type SimilarityOption func(*similarityOptions)
+
+type similarityOptions struct {
+	set   bool
+	value float64
+	err   *error
+}
+
+// EmptyAsOne makes an undefined similarity 1, treating sets with nothing to compare as identical.
+func EmptyAsOne() SimilarityOption {
+	return emptyAs(1)
+}
+
+// EmptyAsZero makes an undefined similarity 0, treating sets with nothing to compare as unrelated.
+func EmptyAsZero() SimilarityOption {
+	return emptyAs(0)
+}
+
+func emptyAs(v float64) SimilarityOption {
+	return func(o *similarityOptions) {
+		o.set, o.value = true, v
+	}
+}
+
+// reportUndefined makes a measure store an *UndefinedSimilarityError in *err when it is
+// undefined and no option chooses a value. It is added after the caller's options by the
+// functions that return an error, with err local to the call.
+func reportUndefined(err *error) SimilarityOption {
+	return func(o *similarityOptions) {
+		o.err = err
+	}
+}
+
+// undefined returns the value of the named measure when it is 0/0: the value chosen by opts,
+// NaN if it is to be reported as an error, or def.
+func undefined(measure string, def float64, opts []SimilarityOption) float64 {
+	var o similarityOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	switch {
+	case o.set:
+		return o.value
+	case o.err != nil:
+		*o.err = &UndefinedSimilarityError{Measure: measure}
+		return math.NaN()
+	}
+	return def
+}
+
+// checked returns the value of measure under opts, or NaN and an *UndefinedSimilarityError if
+// it is undefined and opts choose no value.
+func checked(opts []SimilarityOption, measure func(opts ...SimilarityOption) float64) (float64, error) {
+	var err error
+	v := measure(append(opts[:len(opts):len(opts)], reportUndefined(&err))...)
+	return v, err
+}
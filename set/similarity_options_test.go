@@ -0,0 +1,109 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// degenerateCases lists every similarity measure with operands for which it is 0/0, along with
+// the value it returns by default.
+func degenerateCases() []struct {
+	name    string
+	measure func(opts ...SimilarityOption) float64
+	def     float64
+	checked func(opts ...SimilarityOption) (float64, error)
+} {
+	E, A, U := NewSet(), NewSet(1, 2), NewSet(1, 2, 3)
+	F := NewFuzzySet()
+	return []struct {
+		name    string
+		measure func(opts ...SimilarityOption) float64
+		def     float64
+		checked func(opts ...SimilarityOption) (float64, error)
+	}{
+		{"JaccardSimilarity", func(o ...SimilarityOption) float64 { return JaccardSimilarity(E, E, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return JaccardSimilarityErr(E, E, o...) }},
+		{"DSC", func(o ...SimilarityOption) float64 { return DSC(E, E, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return DSCErr(E, E, o...) }},
+		{"OverlapCoefficient", func(o ...SimilarityOption) float64 { return OverlapCoefficient(E, E, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return OverlapCoefficientErr(E, E, o...) }},
+		{"OverlapCoefficient", func(o ...SimilarityOption) float64 { return OverlapCoefficient(A, E, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return OverlapCoefficientErr(A, E, o...) }},
+		{"TverskyIndex", func(o ...SimilarityOption) float64 { return TverskyIndex(E, E, 1, 1, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return TverskyIndexErr(E, E, 1, 1, o...) }},
+		{"TverskyIndex", func(o ...SimilarityOption) float64 { return TverskyIndex(A, NewSet(3), 0, 0, o...) }, 0, func(o ...SimilarityOption) (float64, error) { return TverskyIndexErr(A, NewSet(3), 0, 0, o...) }},
+		{"OchiaiCoefficient", func(o ...SimilarityOption) float64 { return OchiaiCoefficient(E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return OchiaiCoefficientErr(E, E, o...) }},
+		{"OchiaiCoefficient", func(o ...SimilarityOption) float64 { return OchiaiCoefficient(E, A, o...) }, 0, func(o ...SimilarityOption) (float64, error) { return OchiaiCoefficientErr(E, A, o...) }},
+		{"KulczynskiSimilarity", func(o ...SimilarityOption) float64 { return KulczynskiSimilarity(E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return KulczynskiSimilarityErr(E, E, o...) }},
+		{"KulczynskiSimilarity", func(o ...SimilarityOption) float64 { return KulczynskiSimilarity(A, E, o...) }, 0, func(o ...SimilarityOption) (float64, error) { return KulczynskiSimilarityErr(A, E, o...) }},
+		{"BraunBlanquetSimilarity", func(o ...SimilarityOption) float64 { return BraunBlanquetSimilarity(E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return BraunBlanquetSimilarityErr(E, E, o...) }},
+		{"SimpsonSimilarity", func(o ...SimilarityOption) float64 { return SimpsonSimilarity(E, A, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return SimpsonSimilarityErr(E, A, o...) }},
+		{"SimpleMatchingCoefficient", func(o ...SimilarityOption) float64 { return SimpleMatchingCoefficient(E, E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return SimpleMatchingCoefficientErr(E, E, E, o...) }},
+		{"RogersTanimotoSimilarity", func(o ...SimilarityOption) float64 { return RogersTanimotoSimilarity(E, E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return RogersTanimotoSimilarityErr(E, E, E, o...) }},
+		{"HamannSimilarity", func(o ...SimilarityOption) float64 { return HamannSimilarity(E, E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return HamannSimilarityErr(E, E, E, o...) }},
+		{"SokalSneathSimilarity", func(o ...SimilarityOption) float64 { return SokalSneathSimilarity(E, E, E, o...) }, 1, func(o ...SimilarityOption) (float64, error) { return SokalSneathSimilarityErr(E, E, E, o...) }},
+		{"YuleQ", func(o ...SimilarityOption) float64 { return YuleQ(U, E, A, o...) }, 0, func(o ...SimilarityOption) (float64, error) { return YuleQErr(U, E, A, o...) }},
+		{"PhiCoefficient", func(o ...SimilarityOption) float64 { return PhiCoefficient(U, E, U, o...) }, -1, func(o ...SimilarityOption) (float64, error) { return PhiCoefficientErr(U, E, U, o...) }},
+		{"WeightedJaccardSimilarity", func(o ...SimilarityOption) float64 {
+			return WeightedJaccardSimilarity(Weights{}, Weights{"a": 0}, o...)
+		}, math.NaN(), func(o ...SimilarityOption) (float64, error) {
+			return WeightedJaccardSimilarityErr(Weights{}, Weights{"a": 0}, o...)
+		}},
+		{"WeightedDSC", func(o ...SimilarityOption) float64 { return WeightedDSC(Weights{}, Weights{}, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return WeightedDSCErr(Weights{}, Weights{}, o...) }},
+		{"WeightedOverlapCoefficient", func(o ...SimilarityOption) float64 {
+			return WeightedOverlapCoefficient(Weights{"a": 1}, Weights{}, o...)
+		}, math.NaN(), func(o ...SimilarityOption) (float64, error) {
+			return WeightedOverlapCoefficientErr(Weights{"a": 1}, Weights{}, o...)
+		}},
+		{"ProbabilityJaccardSimilarity", func(o ...SimilarityOption) float64 { return ProbabilityJaccardSimilarity(Weights{}, Weights{}, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) {
+			return ProbabilityJaccardSimilarityErr(Weights{}, Weights{}, o...)
+		}},
+		{"FuzzyJaccardSimilarity", func(o ...SimilarityOption) float64 { return FuzzyJaccardSimilarity(F, F, o...) }, math.NaN(), func(o ...SimilarityOption) (float64, error) { return FuzzyJaccardSimilarityErr(F, F, o...) }},
+	}
+}
+
+func sameFloat(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b)
+}
+
+func Test_SimilarityEmptyPolicies(t *testing.T) {
+	for _, tt := range degenerateCases() {
+		if got := tt.measure(); !sameFloat(got, tt.def) {
+			t.Errorf("%s: expecting a default of %f instead got %f", tt.name, tt.def, got)
+		}
+		if got := tt.measure(EmptyAsOne()); got != 1 {
+			t.Errorf("%s: expecting 1 with EmptyAsOne instead got %f", tt.name, got)
+		}
+		if got := tt.measure(EmptyAsZero()); got != 0 {
+			t.Errorf("%s: expecting 0 with EmptyAsZero instead got %f", tt.name, got)
+		}
+		got, err := tt.checked()
+		var undefinedErr *UndefinedSimilarityError
+		if !math.IsNaN(got) || !errors.Is(err, ErrUndefinedSimilarity) || !errors.As(err, &undefinedErr) || undefinedErr.Measure != tt.name {
+			t.Errorf("%s: expecting NaN and an UndefinedSimilarityError from the Err variant instead got %f, %v", tt.name, got, err)
+		}
+		if got, err := tt.checked(EmptyAsZero()); got != 0 || err != nil {
+			t.Errorf("%s: expecting the Err variant to return 0 with EmptyAsZero instead got %f, %v", tt.name, got, err)
+		}
+	}
+}
+
+func Test_SimilarityOptionsOnDefinedSets(t *testing.T) {
+	A := NewSet(0, 1, 2, 5, 6, 8, 9)
+	B := NewSet(0, 2, 3, 4, 5, 7, 9)
+	if j, err := JaccardSimilarityErr(A, B); j != 0.4 || err != nil {
+		t.Errorf("Expecting 0.4 and no error instead got %f, %v", j, err)
+	}
+	if d := JaccardDistance(NewSet(), NewSet(), EmptyAsOne()); d != 0 {
+		t.Errorf("Expecting two empty sets to be 0 apart instead got %f", d)
+	}
+	if j := JaccardSimilarity(NewSet(), NewSet(), EmptyAsOne(), EmptyAsZero()); j != 0 {
+		t.Errorf("Expecting the last option to win instead got %f", j)
+	}
+	// An error from one call must not leak into the next.
+	if _, err := JaccardSimilarityErr(NewSet(), NewSet()); err == nil {
+		t.Error("Expecting an error for two empty sets")
+	}
+	if j, err := JaccardSimilarityErr(A, B); j != 0.4 || err != nil {
+		t.Errorf("Expecting 0.4 and no error instead got %f, %v", j, err)
+	}
+	if j, err := Jaccard.SimilarityErr(NewSet(), NewSet()); !math.IsNaN(j) || !errors.Is(err, ErrUndefinedSimilarity) {
+		t.Errorf("Expecting NaN and an UndefinedSimilarityError from Measure.SimilarityErr instead got %f, %v", j, err)
+	}
+}
@@ -0,0 +1,393 @@
+package set
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// smallSetThreshold is the number of elements a SmallSet stores inline before upgrading to a map.
+const smallSetThreshold = 16
+
+// SmallSet is a set optimised for a handful of elements. Up to 16 elements are stored inline in an
+// array and looked up with a linear scan, so building one costs a single allocation against
+// three to five for a Set, and Union and Intersect of small sets allocate less. Lookups cost
+// about the same as in a Set's map at that size, a miss in a full array a little more. Past the threshold the elements move into a hash map and
+// the set behaves, and costs, like a Set; so does any result that outgrows the threshold.
+//
+// SmallSet has the same methods as Set, with operations that return a set returning a *SmallSet,
+// and ToSet converts it when a *Set is required. Set itself keeps its map, as its E field is part
+// of its API.
+type SmallSet struct {
+	n     int
+	small [smallSetThreshold]interface{}
+	m     elements
+	sync.Mutex
+}
+
+// NewSmallSet returns a new small set (A) of all unique elements passed into the function call.
+func NewSmallSet(els ...interface{}) *SmallSet {
+	A := &SmallSet{}
+	if len(els) > smallSetThreshold {
+		A.m = make(elements, len(els))
+	}
+	A.Add(els...)
+	return A
+}
+
+// Add inserts one or more elements into A.
+func (A *SmallSet) Add(els ...interface{}) {
+	for _, e := range els {
+		if A.m != nil {
+			A.m[e] = nothing{}
+			continue
+		}
+		if A.index(e) >= 0 {
+			continue
+		}
+		if A.n < smallSetThreshold {
+			A.small[A.n] = e
+			A.n++
+			continue
+		}
+		A.upgrade()
+		A.m[e] = nothing{}
+	}
+}
+
+// upgrade moves the inline elements of A into a map.
+func (A *SmallSet) upgrade() {
+	A.m = make(elements, 2*smallSetThreshold)
+	for i := 0; i < A.n; i++ {
+		A.m[A.small[i]] = nothing{}
+		A.small[i] = nil
+	}
+	A.n = 0
+}
+
+// index returns the position of e in the inline array, or -1. Ints and strings, the most common
+// elements, are compared without the generic interface comparison.
+func (A *SmallSet) index(e interface{}) int {
+	switch x := e.(type) {
+	case int:
+		for i := 0; i < A.n; i++ {
+			if v, ok := A.small[i].(int); ok && v == x {
+				return i
+			}
+		}
+		return -1
+	case string:
+		for i := 0; i < A.n; i++ {
+			if v, ok := A.small[i].(string); ok && v == x {
+				return i
+			}
+		}
+		return -1
+	}
+	for i := 0; i < A.n; i++ {
+		if A.small[i] == e {
+			return i
+		}
+	}
+	return -1
+}
+
+func (A *SmallSet) has(e interface{}) bool {
+	if A.m != nil {
+		_, ok := A.m[e]
+		return ok
+	}
+	return A.index(e) >= 0
+}
+
+func (A *SmallSet) len() int {
+	if A.m != nil {
+		return len(A.m)
+	}
+	return A.n
+}
+
+// each calls fn for every element of A until fn returns false.
+func (A *SmallSet) each(fn func(e interface{}) bool) {
+	if A.m != nil {
+		for e := range A.m {
+			if !fn(e) {
+				return
+			}
+		}
+		return
+	}
+	for i := 0; i < A.n; i++ {
+		if !fn(A.small[i]) {
+			return
+		}
+	}
+}
+
+// Remove deletes one or more existing elements from A.
+func (A *SmallSet) Remove(els ...interface{}) {
+	A.Lock()
+	defer A.Unlock()
+	for _, e := range els {
+		if A.m != nil {
+			delete(A.m, e)
+			continue
+		}
+		if i := A.index(e); i >= 0 {
+			A.n--
+			A.small[i] = A.small[A.n]
+			A.small[A.n] = nil
+		}
+	}
+}
+
+// Contains checks if one or more elements are in A.
+//
+// ∈	in, element of	used to denote that an element is part of a set	1∈1,2,3
+// ∉	not in, not an element of	used to denote than an element is not part of a set	4∉1,2,3
+func (A *SmallSet) Contains(els ...interface{}) bool {
+	A.Lock()
+	defer A.Unlock()
+	for _, e := range els {
+		if !A.has(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cardinality returns the size of A defined as the number of unique elements within A.
+//
+// ∣S∣	cardinality	used to describe the size of a set (refers to the number of unique elements if A is finite)
+func (A *SmallSet) Cardinality() float64 {
+	A.Lock()
+	defer A.Unlock()
+	return float64(A.len())
+}
+
+// Len returns the number of elements in A.
+func (A *SmallSet) Len() int {
+	A.Lock()
+	defer A.Unlock()
+	return A.len()
+}
+
+// Iterate calls fn for every element of A until fn returns false.
+func (A *SmallSet) Iterate(fn func(e interface{}) bool) {
+	A.each(fn)
+}
+
+// SetToSlice converts a set to a slice.
+func (A *SmallSet) SetToSlice() []interface{} {
+	ss := make([]interface{}, 0, A.len())
+	A.each(func(e interface{}) bool {
+		ss = append(ss, e)
+		return true
+	})
+	return ss
+}
+
+// ToSet returns a new *Set with the same elements as A.
+func (A *SmallSet) ToSet() *Set {
+	return NewSet(A.SetToSlice()...)
+}
+
+// String returns a string representation of A.
+func (A *SmallSet) String() (s string) {
+	els := make([]string, 0, A.len())
+	A.each(func(e interface{}) bool {
+		els = append(els, fmt.Sprintf("%v", e))
+		return true
+	})
+	s = fmt.Sprintf("{%v}", strings.Join(els, ", "))
+	return
+}
+
+// IsSubset checks if A is a subset of B.
+//
+// ⊆	subset	set A is a subset of set B when each element in A is also an element in B
+func (A *SmallSet) IsSubset(B Interface) bool {
+	if b, ok := B.(*SmallSet); ok {
+		if A.len() > b.len() {
+			return false
+		}
+		subset := true
+		A.each(func(e interface{}) bool {
+			subset = b.has(e)
+			return subset
+		})
+		return subset
+	}
+	return isSubset(A, B)
+}
+
+// IsEqual checks if A & B have the same elements.
+func (A *SmallSet) IsEqual(B Interface) bool {
+	return A.len() == B.Len() && A.IsSubset(B)
+}
+
+// Union creates a new set (C) from elements in A & B.
+// ∪ 	union	a set with the elements in set A or in set B
+func (A *SmallSet) Union(B Interface) *SmallSet {
+	C := &SmallSet{}
+	if n := A.len() + B.Len(); n > smallSetThreshold {
+		C.m = make(elements, n)
+	}
+	for i := 0; i < A.n; i++ {
+		C.Add(A.small[i])
+	}
+	for e := range A.m {
+		C.Add(e)
+	}
+	if b, ok := B.(*SmallSet); ok {
+		for i := 0; i < b.n; i++ {
+			C.Add(b.small[i])
+		}
+		for e := range b.m {
+			C.Add(e)
+		}
+		return C
+	}
+	B.Iterate(func(e interface{}) bool {
+		C.Add(e)
+		return true
+	})
+	return C
+}
+
+// Intersect creates a new set (C) from elements in both A & B.
+// ∩	intersection	a set with the elements in set A and in set B
+func (A *SmallSet) Intersect(B Interface) *SmallSet {
+	C := &SmallSet{}
+	if b, ok := B.(*SmallSet); ok {
+		small, big := A, b
+		if A.len() > b.len() {
+			small, big = b, A
+		}
+		for i := 0; i < small.n; i++ {
+			if big.has(small.small[i]) {
+				C.Add(small.small[i])
+			}
+		}
+		for e := range small.m {
+			if big.has(e) {
+				C.Add(e)
+			}
+		}
+		return C
+	}
+	var small, big Interface = A, B
+	if A.len() > B.Len() {
+		small, big = B, A
+	}
+	small.Iterate(func(e interface{}) bool {
+		if big.Contains(e) {
+			C.Add(e)
+		}
+		return true
+	})
+	return C
+}
+
+// Subset returns a new set (C) of the elements of A that are also in B.
+//
+// ⊆	subset	set A is a subset of set B when each element in A is also an element in B
+func (A *SmallSet) Subset(B Interface) *SmallSet {
+	return A.Intersect(B)
+}
+
+// IsDisjoint checks if A & B have no elements in common.
+func (A *SmallSet) IsDisjoint(B Interface) bool {
+	return intersectionLen(A, B) == 0
+}
+
+// IsEquivalent checks if A & B have the same Cardinality.
+func (A *SmallSet) IsEquivalent(B Interface) bool {
+	return A.len() == B.Len()
+}
+
+// IsProperSubset checks if A is a subset of B and A≠B.
+//
+// ⊂	proper subset	set A is a proper subset of set B when each element in A is also an element in B and A≠B
+func (A *SmallSet) IsProperSubset(B Interface) bool {
+	return A.len() < B.Len() && A.IsSubset(B)
+}
+
+// IsSuperset checks if A is a superset of B.
+//
+// ⊇	superset	set A is a superset of set B when B is a subset of A
+func (A *SmallSet) IsSuperset(B Interface) bool {
+	if b, ok := B.(*SmallSet); ok {
+		return b.IsSubset(A)
+	}
+	return isSubset(B, A)
+}
+
+// IsProperSuperset checks if A is a superset of B and A≠B.
+func (A *SmallSet) IsProperSuperset(B Interface) bool {
+	return A.len() > B.Len() && A.IsSuperset(B)
+}
+
+// Difference creates a new set (C) from elements in A that are not in B.
+//
+// −, ∖	set difference	elements in set A that are not in B
+func (A *SmallSet) Difference(B Interface) *SmallSet {
+	C := &SmallSet{}
+	A.each(func(e interface{}) bool {
+		if !B.Contains(e) {
+			C.Add(e)
+		}
+		return true
+	})
+	return C
+}
+
+// SymmetricDifference creates a new set (C) from elements in exactly one of A & B.
+//
+// ∆	symmetric difference	elements in set A or in set B but not in both
+func (A *SmallSet) SymmetricDifference(B Interface) *SmallSet {
+	C := A.Difference(B)
+	B.Iterate(func(e interface{}) bool {
+		if !A.has(e) {
+			C.Add(e)
+		}
+		return true
+	})
+	return C
+}
+
+// Complement returns the elements of A outside the union of the sets U, as the package Complement does.
+func (A *SmallSet) Complement(U ...Interface) *SmallSet {
+	C := &SmallSet{}
+	A.each(func(e interface{}) bool {
+		for _, u := range U {
+			if u.Contains(e) {
+				return true
+			}
+		}
+		C.Add(e)
+		return true
+	})
+	return C
+}
+
+// Powerset returns the set of all subsets of A, as *SmallSet.
+func (A *SmallSet) Powerset() *SmallSet {
+	els := A.SetToSlice()
+	B := &SmallSet{}
+	for i := 0; i < A.PowersetCardinality(); i++ {
+		S := &SmallSet{}
+		for j, e := range els {
+			if i&(1<<j) != 0 {
+				S.Add(e)
+			}
+		}
+		B.Add(S)
+	}
+	return B
+}
+
+// PowersetCardinality returns the number of subsets of A, 2^|A|.
+func (A *SmallSet) PowersetCardinality() int {
+	return 1 << uint(A.len())
+}
@@ -0,0 +1,242 @@
+package set
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_NewSmallSet(t *testing.T) {
+	A := NewSmallSet(1, 1, 2, 3, 4, 5)
+	if A.Cardinality() != 5 {
+		t.Errorf("A should have 5 elements. Instead it has a cardinality of %f", A.Cardinality())
+	}
+	A.Remove(1)
+	if A.Cardinality() != 4 || A.Contains(1) {
+		t.Errorf("A should have 4 elements after one was removed. Instead it is %v", A)
+	}
+	if !A.Contains(2, 3, 4, 5) {
+		t.Errorf("A should contain 2, 3, 4 & 5. Instead it is %v", A)
+	}
+	if !A.ToSet().IsEqual(NewSet(2, 3, 4, 5)) {
+		t.Errorf("Expecting ToSet to keep the elements of %v", A)
+	}
+}
+
+func Test_SmallSetUpgrade(t *testing.T) {
+	A := NewSmallSet()
+	for i := 0; i < 3*smallSetThreshold; i++ {
+		A.Add(i, i)
+		if A.Cardinality() != float64(i+1) {
+			t.Fatalf("Expecting a cardinality of %d instead got %f", i+1, A.Cardinality())
+		}
+	}
+	if A.m == nil {
+		t.Error("Expecting A to have upgraded to a map")
+	}
+	for i := 0; i < 3*smallSetThreshold; i++ {
+		if !A.Contains(i) {
+			t.Errorf("Expecting A to contain %d after upgrading", i)
+		}
+	}
+	A.Remove(0, 1)
+	if A.Contains(0) || A.Cardinality() != 3*smallSetThreshold-2 {
+		t.Errorf("Expecting 0 & 1 to be removed from %v", A)
+	}
+	B := NewSmallSet(make([]interface{}, 20)...)
+	if B.Cardinality() != 1 {
+		t.Errorf("Expecting duplicates to be ignored instead got %v", B)
+	}
+}
+
+func Test_SmallSetUnionIntersect(t *testing.T) {
+	A := NewSmallSet(1, 2, 3, 4)
+	B := NewSmallSet(3, 4, 5)
+	if U := A.Union(B); !U.IsEqual(NewSmallSet(1, 2, 3, 4, 5)) {
+		t.Errorf("Expecting a union of {1, 2, 3, 4, 5} instead got %v", U)
+	}
+	if I := A.Intersect(B); !I.IsEqual(NewSmallSet(3, 4)) {
+		t.Errorf("Expecting an intersection of {3, 4} instead got %v", I)
+	}
+	if !NewSmallSet(3).IsSubset(B) || A.IsSubset(B) {
+		t.Error("Expecting {3} to be a subset of B and A not to be")
+	}
+	big := NewSmallSet()
+	for i := 0; i < 40; i++ {
+		big.Add(i)
+	}
+	if U := A.Union(big); U.Cardinality() != 40 {
+		t.Errorf("Expecting a union of 40 elements instead got %f", U.Cardinality())
+	}
+	if I := big.Intersect(B); !I.IsEqual(B) {
+		t.Errorf("Expecting the intersection to be %v instead got %v", B, I)
+	}
+}
+
+func Test_SmallSetMethodParity(t *testing.T) {
+	small := reflect.TypeOf(&SmallSet{})
+	set := reflect.TypeOf(&Set{})
+	for i := 0; i < set.NumMethod(); i++ {
+		if _, ok := small.MethodByName(set.Method(i).Name); !ok {
+			t.Errorf("Expecting SmallSet to have the %s method of Set", set.Method(i).Name)
+		}
+	}
+}
+
+func Test_SmallSetAgreesWithSet(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	random := func() []interface{} {
+		els := make([]interface{}, r.Intn(2*smallSetThreshold))
+		for i := range els {
+			els[i] = r.Intn(3 * smallSetThreshold)
+		}
+		return els
+	}
+	for trial := 0; trial < 200; trial++ {
+		a, b := random(), random()
+		A, B := NewSet(a...), NewSet(b...)
+		SA, SB := NewSmallSet(a...), NewSmallSet(b...)
+		for name, c := range map[string][2]bool{
+			"IsSubset":         {A.IsSubset(B), SA.IsSubset(SB)},
+			"IsProperSubset":   {A.IsProperSubset(B), SA.IsProperSubset(SB)},
+			"IsSuperset":       {A.IsSuperset(B), SA.IsSuperset(SB)},
+			"IsProperSuperset": {A.IsProperSuperset(B), SA.IsProperSuperset(SB)},
+			"IsDisjoint":       {A.IsDisjoint(B), SA.IsDisjoint(SB)},
+			"IsEquivalent":     {A.IsEquivalent(B), SA.IsEquivalent(SB)},
+			"IsEqual":          {A.IsEqual(B), SA.IsEqual(SB)},
+		} {
+			if c[0] != c[1] {
+				t.Errorf("Expecting %s of %v and %v to be %v", name, A, B, c[0])
+			}
+		}
+		sub := Subset(A, B)
+		for name, c := range map[string][2]Interface{
+			"Union":               {A.Union(B), SA.Union(SB)},
+			"Intersect":           {A.Intersect(B), SA.Intersect(SB)},
+			"Subset":              {&sub, SA.Subset(SB)},
+			"Difference":          {Difference(A, B), SA.Difference(SB)},
+			"SymmetricDifference": {SymetricDifferencec(A, B), SA.SymmetricDifference(SB)},
+			"Complement":          {Complement(A, B), SA.Complement(SB)},
+		} {
+			if !Equals(c[0], c[1]) {
+				t.Errorf("Expecting %s of %v and %v to be %v instead got %v", name, A, B, c[0], c[1])
+			}
+		}
+	}
+	P := NewSmallSet(1, 2, 3).Powerset()
+	if P.Len() != 8 || NewSmallSet(1, 2, 3).PowersetCardinality() != 8 {
+		t.Errorf("Expecting 8 subsets instead got %v", P)
+	}
+	found := false
+	P.Iterate(func(e interface{}) bool {
+		found = e.(*SmallSet).IsEqual(NewSet(1, 3))
+		return !found
+	})
+	if !found {
+		t.Errorf("Expecting {1, 3} in the powerset %v", P)
+	}
+}
+
+// Sinks stop the compiler from optimising the benchmarked calls away.
+var (
+	setSink      *Set
+	smallSetSink *SmallSet
+	boolSink     bool
+)
+
+// benchmarkSizes are the set sizes compared, from one element up to the inline threshold.
+var benchmarkSizes = []int{1, 2, 4, 8, 12, smallSetThreshold}
+
+func benchmarkElements(from, n int) []interface{} {
+	els := make([]interface{}, n)
+	for i := range els {
+		els[i] = from + i
+	}
+	return els
+}
+
+// The benchmarks compare Set and SmallSet for each size, with both a hit and a miss for
+// Contains, since a linear scan pays most on a miss.
+func BenchmarkNew(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		els := benchmarkElements(0, n)
+		b.Run(fmt.Sprintf("Set/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				setSink = NewSet(els...)
+			}
+		})
+		b.Run(fmt.Sprintf("SmallSet/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				smallSetSink = NewSmallSet(els...)
+			}
+		})
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		els := benchmarkElements(0, n)
+		A, S := NewSet(els...), NewSmallSet(els...)
+		for _, probe := range []struct {
+			name string
+			e    interface{}
+		}{{"hit", n - 1}, {"miss", -1}} {
+			e := probe.e
+			b.Run(fmt.Sprintf("Set/%d/%s", n, probe.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					boolSink = A.Contains(e)
+				}
+			})
+			b.Run(fmt.Sprintf("SmallSet/%d/%s", n, probe.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					boolSink = S.Contains(e)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		x, y := benchmarkElements(0, n), benchmarkElements(n/2, n)
+		A, B := NewSet(x...), NewSet(y...)
+		SA, SB := NewSmallSet(x...), NewSmallSet(y...)
+		b.Run(fmt.Sprintf("Set/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				setSink = A.Union(B)
+			}
+		})
+		b.Run(fmt.Sprintf("SmallSet/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				smallSetSink = SA.Union(SB)
+			}
+		})
+	}
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		x, y := benchmarkElements(0, n), benchmarkElements(n/2, n)
+		A, B := NewSet(x...), NewSet(y...)
+		SA, SB := NewSmallSet(x...), NewSmallSet(y...)
+		b.Run(fmt.Sprintf("Set/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				setSink = A.Intersect(B)
+			}
+		})
+		b.Run(fmt.Sprintf("SmallSet/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				smallSetSink = SA.Intersect(SB)
+			}
+		})
+	}
+}
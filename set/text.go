@@ -0,0 +1,174 @@
+package set
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextOption configures how text is normalised and shingled.
+type TextOption func(*textOptions)
+
+type textOptions struct {
+	normalizer func(string) string
+	compat     bool
+	lower      bool
+	strip      bool
+	collapse   bool
+	pad        rune
+	padded     bool
+}
+
+// WithNormalizer applies f to text before every other normalisation. It is the way to get
+// full Unicode normalisation, which needs tables this package does not carry: pass
+// norm.NFKC.String from golang.org/x/text/unicode/norm for NFKC.
+func WithNormalizer(f func(string) string) TextOption {
+	return func(o *textOptions) { o.normalizer = f }
+}
+
+// CompatibilityFold replaces the compatibility characters most common in Latin text with their
+// plain equivalents, as NFKC does: fullwidth ASCII, compatibility spaces, the f-ligatures,
+// superscript and subscript digits and the ellipsis. It is not NFKC: it only covers those
+// characters, and leaves accents as they are, composed or not, so text that differs only in
+// its Unicode normal form can still give different n-grams. Use WithNormalizer for NFKC.
+func CompatibilityFold() TextOption {
+	return func(o *textOptions) { o.compat = true }
+}
+
+// LowerCase maps each rune of text to the lower case of its upper case, which also lowers case
+// variants such as the long s. It is a rune-by-rune mapping rather than Unicode case folding,
+// so foldings that change the length of text, such as ß to ss, are not made.
+func LowerCase() TextOption {
+	return func(o *textOptions) { o.lower = true }
+}
+
+// StripPunctuation removes the Unicode punctuation characters from text.
+func StripPunctuation() TextOption {
+	return func(o *textOptions) { o.strip = true }
+}
+
+// CollapseWhitespace replaces every run of white space with a single space and trims white
+// space at either end.
+func CollapseWhitespace() TextOption {
+	return func(o *textOptions) { o.collapse = true }
+}
+
+// Padding pads text with n-1 copies of r at either end before taking character n-grams, so that
+// the first and last characters appear in as many n-grams as the others.
+func Padding(r rune) TextOption {
+	return func(o *textOptions) { o.pad, o.padded = r, true }
+}
+
+func textConfig(opts []TextOption) textOptions {
+	var o textOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+var compatibilityReplacer = strings.NewReplacer(
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl", "ﬅ", "st", "ﬆ", "st",
+	"…", "...", "‥", "..",
+)
+
+func compatibilityRune(r rune) rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E: // fullwidth ASCII
+		return r - 0xFEE0
+	case r == 0x00A0, r == 0x3000, r >= 0x2000 && r <= 0x200A, r == 0x202F, r == 0x205F:
+		return ' '
+	case r == '¹':
+		return '1'
+	case r == '²':
+		return '2'
+	case r == '³':
+		return '3'
+	case r == 0x2070, r >= 0x2074 && r <= 0x2079: // superscript digits
+		return '0' + r - 0x2070
+	case r >= 0x2080 && r <= 0x2089: // subscript digits
+		return '0' + r - 0x2080
+	}
+	return r
+}
+
+// NormalizeText returns s with the normalisations of opts applied, in the order WithNormalizer,
+// CompatibilityFold, LowerCase, StripPunctuation, CollapseWhitespace.
+func NormalizeText(s string, opts ...TextOption) string {
+	return textConfig(opts).normalize(s)
+}
+
+func (o textOptions) normalize(s string) string {
+	if o.normalizer != nil {
+		s = o.normalizer(s)
+	}
+	if o.compat {
+		s = compatibilityReplacer.Replace(strings.Map(compatibilityRune, s))
+	}
+	if o.lower {
+		s = strings.Map(func(r rune) rune { return unicode.ToLower(unicode.ToUpper(r)) }, s)
+	}
+	if o.strip {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if o.collapse {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+// CharacterNGrams returns the set of strings of n consecutive characters (runes) of the
+// normalised s. A non-empty string shorter than n gives a set of itself alone.
+//
+// CharacterNGrams("night", 2) = {"ni", "ig", "gh", "ht"}
+func CharacterNGrams(s string, n int, opts ...TextOption) *Set {
+	o := textConfig(opts)
+	runes := []rune(o.normalize(s))
+	if o.padded && len(runes) > 0 && n > 1 {
+		pad := []rune(strings.Repeat(string(o.pad), n-1))
+		runes = append(append(pad, runes...), pad...)
+	}
+	return nGrams(len(runes), n, func(i, j int) string { return string(runes[i:j]) })
+}
+
+// WordNGrams returns the set of the runs of n consecutive words (w-shingles) of the normalised
+// s, joined by single spaces. Words are separated by white space. Text of fewer than n words
+// gives a set of all of its words as one shingle.
+//
+// WordNGrams("a rose is a rose", 2) = {"a rose", "rose is", "is a"}
+func WordNGrams(s string, n int, opts ...TextOption) *Set {
+	words := strings.Fields(textConfig(opts).normalize(s))
+	return nGrams(len(words), n, func(i, j int) string { return strings.Join(words[i:j], " ") })
+}
+
+// nGrams returns the set of gram(i, i+n) for every run of n of the l units of a text.
+func nGrams(l, n int, gram func(i, j int) string) *Set {
+	S := NewSet()
+	if l == 0 || n < 1 {
+		return S
+	}
+	if l < n {
+		S.Add(gram(0, l))
+		return S
+	}
+	for i := 0; i+n <= l; i++ {
+		S.Add(gram(i, i+n))
+	}
+	return S
+}
+
+// HashShingles returns the set of the 64-bit hashes of the elements of A, such as the n-grams of
+// a text, which takes less memory and compares faster than the strings. Hashes are stable across
+// processes for a given seed, and distinct elements collide with negligible probability.
+func HashShingles(A Interface, seed uint64) *Set {
+	H := NewSet()
+	A.Iterate(func(e interface{}) bool {
+		H.Add(hashElement(e, seed))
+		return true
+	})
+	return H
+}
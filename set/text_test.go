@@ -0,0 +1,72 @@
+package set
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_CharacterNGrams(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		opts []TextOption
+		want *Set
+	}{
+		{"night", 2, nil, NewSet("ni", "ig", "gh", "ht")},
+		{"ab", 2, []TextOption{Padding('#')}, NewSet("#a", "ab", "b#")},
+		{"日本語", 2, nil, NewSet("日本", "本語")},
+		{"ab", 3, nil, NewSet("ab")},
+		{"", 2, nil, NewSet()},
+		{"NiGHT", 2, []TextOption{LowerCase()}, NewSet("ni", "ig", "gh", "ht")},
+	}
+	for _, tt := range tests {
+		if got := CharacterNGrams(tt.s, tt.n, tt.opts...); !got.IsEqual(tt.want) {
+			t.Errorf("%q: expecting %v instead got %v", tt.s, tt.want, got)
+		}
+	}
+	if dsc := DSC(CharacterNGrams("night", 2), CharacterNGrams("nacht", 2)); dsc != 0.25 {
+		t.Errorf("Expecting a DSC of 0.25 instead got %f", dsc)
+	}
+}
+
+func Test_WordNGrams(t *testing.T) {
+	got := WordNGrams("A rose is  a rose, is a ROSE.", 2, LowerCase(), StripPunctuation())
+	if want := NewSet("a rose", "rose is", "is a"); !got.IsEqual(want) {
+		t.Errorf("Expecting %v instead got %v", want, got)
+	}
+	if got := WordNGrams("just two", 3); !got.IsEqual(NewSet("just two")) {
+		t.Errorf("Expecting a single shingle instead got %v", got)
+	}
+}
+
+func Test_NormalizeText(t *testing.T) {
+	tests := []struct {
+		s, want string
+		opts    []TextOption
+	}{
+		{"Ｈｅｌｌｏ,　 Wörld!", "hello wörld", []TextOption{CompatibilityFold(), LowerCase(), StripPunctuation(), CollapseWhitespace()}},
+		{"ﬁne ﬂour…", "fine flour...", []TextOption{CompatibilityFold()}},
+		{"x² + H₂O", "x2 + H2O", []TextOption{CompatibilityFold()}},
+		{"ſtraße", "straße", []TextOption{LowerCase()}},
+		{"  tabs\tand\nlines ", "tabs and lines", []TextOption{CollapseWhitespace()}},
+		{"Mixed", "MIXED", []TextOption{WithNormalizer(strings.ToUpper)}},
+		{"unchanged, Text", "unchanged, Text", nil},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.s, tt.opts...); got != tt.want {
+			t.Errorf("%q: expecting %q instead got %q", tt.s, tt.want, got)
+		}
+	}
+}
+
+func Test_HashShingles(t *testing.T) {
+	A := CharacterNGrams("the quick brown fox", 3)
+	B := CharacterNGrams("the quick brown dog", 3)
+	HA, HB := HashShingles(A, 1), HashShingles(B, 1)
+	if HA.Len() != A.Len() || JaccardSimilarity(HA, HB) != JaccardSimilarity(A, B) {
+		t.Errorf("Expecting hashing to keep sizes and similarity, got %d & %f", HA.Len(), JaccardSimilarity(HA, HB))
+	}
+	if !HashShingles(A, 1).IsEqual(HA) || HashShingles(A, 2).IsEqual(HA) {
+		t.Error("Expecting hashes to depend only on the shingles and the seed")
+	}
+}
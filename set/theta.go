@@ -0,0 +1,190 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidSize is returned when a sketch is created with a non-positive size.
+var ErrInvalidSize = errors.New("set: sketch size must be positive")
+
+// ThetaSketch is a K-minimum-values sketch. It keeps the hashes of the elements added to it
+// that are below a threshold θ, lowering θ so that about k hashes are kept. Every hash is kept
+// with probability θ, so the number kept divided by θ estimates the number of distinct elements
+// with a relative standard error of about 1/√k.
+//
+// Unlike HyperLogLog, theta sketches are closed under union, intersection and difference: the
+// result of a set expression on sketches is itself a sketch of the result of that expression on
+// the underlying sets.
+type ThetaSketch struct {
+	k      int
+	theta  uint64
+	hashes map[uint64]nothing
+}
+
+// NewThetaSketch returns an empty sketch that keeps about k hashes.
+// ErrInvalidSize is returned if k is not positive.
+func NewThetaSketch(k int) (*ThetaSketch, error) {
+	if k < 1 {
+		return nil, ErrInvalidSize
+	}
+	return newThetaSketch(k, math.MaxUint64), nil
+}
+
+func newThetaSketch(k int, theta uint64) *ThetaSketch {
+	return &ThetaSketch{k: k, theta: theta, hashes: make(map[uint64]nothing)}
+}
+
+// ThetaSketchFromSet returns a sketch of size k holding every element of A.
+func ThetaSketchFromSet(A Interface, k int) (*ThetaSketch, error) {
+	s, err := NewThetaSketch(k)
+	if err != nil {
+		return nil, err
+	}
+	A.Iterate(func(e interface{}) bool {
+		s.Add(e)
+		return true
+	})
+	return s, nil
+}
+
+// Add inserts one or more elements into s.
+func (s *ThetaSketch) Add(els ...interface{}) {
+	for _, e := range els {
+		s.insert(hashElement(e, 0))
+	}
+}
+
+func (s *ThetaSketch) insert(h uint64) {
+	if h >= s.theta {
+		return
+	}
+	s.hashes[h] = nothing{}
+	// Trimming lazily keeps updates amortised O(1). In between, s keeps more than k hashes,
+	// which is still a valid, and slightly more accurate, sketch.
+	if len(s.hashes) > 2*s.k {
+		s.trim()
+	}
+}
+
+// trim lowers θ to the (k+1)th smallest hash, keeping only the k smallest.
+func (s *ThetaSketch) trim() {
+	if len(s.hashes) <= s.k {
+		return
+	}
+	sorted := s.sorted()
+	s.theta = sorted[s.k]
+	for _, h := range sorted[s.k:] {
+		delete(s.hashes, h)
+	}
+}
+
+func (s *ThetaSketch) sorted() []uint64 {
+	sorted := make([]uint64, 0, len(s.hashes))
+	for h := range s.hashes {
+		sorted = append(sorted, h)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// Theta returns the sampling probability θ of s, which is 1 while s is exact.
+func (s *ThetaSketch) Theta() float64 {
+	if s.theta == math.MaxUint64 {
+		return 1
+	}
+	return math.Ldexp(float64(s.theta), -64)
+}
+
+// Retained returns the number of hashes kept by s.
+func (s *ThetaSketch) Retained() int {
+	return len(s.hashes)
+}
+
+// IsExact checks if s has kept every hash, in which case its estimates are exact.
+func (s *ThetaSketch) IsExact() bool {
+	return s.theta == math.MaxUint64
+}
+
+// Cardinality returns the estimated number of distinct elements in s.
+//
+// n* = |retained| / θ
+func (s *ThetaSketch) Cardinality() float64 {
+	return float64(len(s.hashes)) / s.Theta()
+}
+
+// LowerBound returns a lower bound on the number of distinct elements in s, z standard
+// deviations below the estimate. z = 2 gives roughly 95% confidence. The number of hashes kept
+// is binomially distributed with success probability θ, which gives the bound
+//
+// (n - z·√(n(1-θ))) / θ
+func (s *ThetaSketch) LowerBound(z float64) float64 {
+	n, p := float64(len(s.hashes)), s.Theta()
+	return math.Max(n, (n-z*math.Sqrt(n*(1-p)))/p)
+}
+
+// UpperBound returns an upper bound on the number of distinct elements in s, z standard
+// deviations above the estimate.
+//
+// (n + z·√(max(n,1)(1-θ))) / θ
+func (s *ThetaSketch) UpperBound(z float64) float64 {
+	n, p := float64(len(s.hashes)), s.Theta()
+	return (n + z*math.Sqrt(math.Max(n, 1)*(1-p))) / p
+}
+
+func minTheta(s, t *ThetaSketch) uint64 {
+	if s.theta < t.theta {
+		return s.theta
+	}
+	return t.theta
+}
+
+func minK(s, t *ThetaSketch) int {
+	if s.k < t.k {
+		return s.k
+	}
+	return t.k
+}
+
+// Union returns a sketch of the elements in s or t.
+//
+// θ = min(θs, θt), S = {h ∈ s∪t : h < θ}
+func (s *ThetaSketch) Union(t *ThetaSketch) *ThetaSketch {
+	u := newThetaSketch(minK(s, t), minTheta(s, t))
+	for _, from := range []*ThetaSketch{s, t} {
+		for h := range from.hashes {
+			if h < u.theta {
+				u.hashes[h] = nothing{}
+			}
+		}
+	}
+	u.trim()
+	return u
+}
+
+// Intersect returns a sketch of the elements in both s & t.
+//
+// θ = min(θs, θt), S = {h ∈ s∩t : h < θ}
+func (s *ThetaSketch) Intersect(t *ThetaSketch) *ThetaSketch {
+	u := newThetaSketch(minK(s, t), minTheta(s, t))
+	for h := range s.hashes {
+		if _, ok := t.hashes[h]; ok && h < u.theta {
+			u.hashes[h] = nothing{}
+		}
+	}
+	return u
+}
+
+// Difference returns a sketch of the elements in s that are not in t, the A-not-B expression.
+//
+// θ = min(θs, θt), S = {h ∈ s−t : h < θ}
+func (s *ThetaSketch) Difference(t *ThetaSketch) *ThetaSketch {
+	u := newThetaSketch(minK(s, t), minTheta(s, t))
+	for h := range s.hashes {
+		if _, ok := t.hashes[h]; !ok && h < u.theta {
+			u.hashes[h] = nothing{}
+		}
+	}
+	return u
+}
@@ -0,0 +1,57 @@
+package set
+
+import "testing"
+
+func Test_NewThetaSketch(t *testing.T) {
+	if _, err := NewThetaSketch(0); err != ErrInvalidSize {
+		t.Errorf("Expecting ErrInvalidSize instead got %v", err)
+	}
+	s, _ := ThetaSketchFromSet(intSet(0, 100), 1024)
+	if !s.IsExact() || s.Cardinality() != 100 {
+		t.Errorf("Expecting an exact count of 100 instead got %f", s.Cardinality())
+	}
+	if s.LowerBound(2) != 100 || s.UpperBound(2) != 100 {
+		t.Errorf("Expecting exact bounds instead got [%f, %f]", s.LowerBound(2), s.UpperBound(2))
+	}
+}
+
+func Test_ThetaSketchCardinality(t *testing.T) {
+	for _, n := range []int{5000, 50000, 200000} {
+		s, _ := ThetaSketchFromSet(intSet(0, n), 1024)
+		if s.IsExact() {
+			t.Fatalf("Expecting a sketch of %d elements to sample", n)
+		}
+		if s.Retained() < 1024 || s.Retained() > 2048 {
+			t.Errorf("Expecting between k and 2k hashes to be kept instead got %d", s.Retained())
+		}
+		if float64(n) < s.LowerBound(3) || float64(n) > s.UpperBound(3) {
+			t.Errorf("Expecting %d within [%f, %f]", n, s.LowerBound(3), s.UpperBound(3))
+		}
+	}
+}
+
+func Test_ThetaSketchSetExpressions(t *testing.T) {
+	A := intSet(0, 60000)
+	B := intSet(30000, 100000)
+	sa, _ := ThetaSketchFromSet(A, 4096)
+	sb, _ := ThetaSketchFromSet(B, 4096)
+	tests := []struct {
+		name  string
+		s     *ThetaSketch
+		exact float64
+	}{
+		{"union", sa.Union(sb), Union(A, B).Cardinality()},
+		{"intersection", sa.Intersect(sb), Intersect(A, B).Cardinality()},
+		{"A not B", sa.Difference(sb), Difference(A, B).Cardinality()},
+		{"B not A", sb.Difference(sa), Difference(B, A).Cardinality()},
+	}
+	for _, tt := range tests {
+		if tt.exact < tt.s.LowerBound(3) || tt.exact > tt.s.UpperBound(3) {
+			t.Errorf("%s: expecting %f within [%f, %f], estimate %f", tt.name, tt.exact, tt.s.LowerBound(3), tt.s.UpperBound(3), tt.s.Cardinality())
+		}
+	}
+	disjoint, _ := ThetaSketchFromSet(intSet(200000, 260000), 4096)
+	if I := sa.Intersect(disjoint); I.Cardinality() != 0 || I.UpperBound(2) <= 0 {
+		t.Errorf("Expecting an empty intersection with a positive upper bound instead got %f, %f", I.Cardinality(), I.UpperBound(2))
+	}
+}
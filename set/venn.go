@@ -0,0 +1,147 @@
+package set
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrTooManySets is returned when a family has too many members for every combination of them to be listed.
+var ErrTooManySets = errors.New("set: too many sets to combine")
+
+// ErrInclusionExclusion is returned when the size of a union counted by inclusion–exclusion differs from the sum of its Venn regions.
+var ErrInclusionExclusion = errors.New("set: inclusion–exclusion disagrees with the Venn regions")
+
+// MaxVennSets is the largest family whose Venn regions are listed, about a million regions.
+const MaxVennSets = 20
+
+// Selection is a non-empty choice of members of a Family, with bit i set when member i is chosen.
+type Selection uint64
+
+// Contains reports whether member i is chosen.
+func (s Selection) Contains(i int) bool {
+	return i >= 0 && i < 64 && s&(1<<uint(i)) != 0
+}
+
+// Len returns the number of members chosen.
+func (s Selection) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Indices returns the indices of the members chosen in ascending order.
+func (s Selection) Indices() []int {
+	idx := make([]int, 0, s.Len())
+	for v := uint64(s); v != 0; v &= v - 1 {
+		idx = append(idx, bits.TrailingZeros64(v))
+	}
+	return idx
+}
+
+// VennRegion is an atomic region of the Venn diagram of a family: the set of the elements in
+// every member chosen by In and in no other. Its cardinality is Set.Len().
+//
+// region(S) = ⋂_{i∈S} Aᵢ − ⋃_{i∉S} Aᵢ
+type VennRegion struct {
+	In  Selection
+	Set *Set
+}
+
+// VennRegions returns the 2ⁿ−1 atomic regions of the Venn diagram of the n members of F, empty
+// or not, in order of In. The regions are disjoint and their union is F.BigUnion(). They are
+// found in one pass over the members, from the members holding each element.
+// ErrTooManySets is returned if F has more than MaxVennSets members.
+func (F Family) VennRegions() ([]VennRegion, error) {
+	if len(F) > MaxVennSets {
+		return nil, ErrTooManySets
+	}
+	regions := make([]VennRegion, 1<<uint(len(F))-1)
+	for i := range regions {
+		regions[i] = VennRegion{In: Selection(i + 1), Set: NewSet()}
+	}
+	for e, in := range F.membership() {
+		regions[in-1].Set.E[e] = nothing{}
+	}
+	return regions, nil
+}
+
+// membership returns the selection of the members of F holding each element of their union.
+func (F Family) membership() map[interface{}]Selection {
+	in := make(map[interface{}]Selection)
+	for i, A := range F {
+		A.Iterate(func(e interface{}) bool {
+			in[e] |= 1 << uint(i)
+			return true
+		})
+	}
+	return in
+}
+
+// IntersectionSizes returns the size of the intersection of every non-empty selection of the
+// members of F whose intersection is not empty. Intersections are built by adding members in
+// order to smaller ones, so a selection is not visited once a subset of it has an empty
+// intersection.
+// ErrTooManySets is returned if F has more than 64 members.
+//
+// sizes[S] = |⋂_{i∈S} Aᵢ|
+func (F Family) IntersectionSizes() (map[Selection]int, error) {
+	if len(F) > 64 {
+		return nil, ErrTooManySets
+	}
+	sizes := make(map[Selection]int)
+	var extend func(s Selection, next int, I Interface)
+	extend = func(s Selection, next int, I Interface) {
+		for i := next; i < len(F); i++ {
+			J := F[i]
+			if I != nil {
+				J = Intersect(I, F[i])
+			}
+			if J.Len() == 0 {
+				continue
+			}
+			t := s | 1<<uint(i)
+			sizes[t] = J.Len()
+			extend(t, i+1, J)
+		}
+	}
+	extend(0, 0, nil)
+	return sizes, nil
+}
+
+// InclusionExclusion returns the size of the union of a family from the sizes of the
+// intersections of its members alone, such as those returned by IntersectionSizes. Selections
+// missing from sizes count as empty intersections.
+//
+// |⋃ᵢ Aᵢ| = Σ_{S≠∅} (−1)^{|S|+1} |⋂_{i∈S} Aᵢ|
+func InclusionExclusion(sizes map[Selection]int) (n int) {
+	for s, size := range sizes {
+		if s.Len()%2 == 1 {
+			n += size
+		} else {
+			n -= size
+		}
+	}
+	return
+}
+
+// CheckInclusionExclusion returns the size of the union of the members of F counted twice: as
+// the sum of the sizes of its Venn regions, and by inclusion–exclusion from the sizes of the
+// intersections of its members.
+// ErrTooManySets is returned if F has more than MaxVennSets members, and ErrInclusionExclusion
+// if the two counts differ.
+func (F Family) CheckInclusionExclusion() (int, error) {
+	regions, err := F.VennRegions()
+	if err != nil {
+		return 0, err
+	}
+	sum := 0
+	for _, r := range regions {
+		sum += r.Set.Len()
+	}
+	sizes, err := F.IntersectionSizes()
+	if err != nil {
+		return 0, err
+	}
+	if InclusionExclusion(sizes) != sum {
+		return 0, ErrInclusionExclusion
+	}
+	return sum, nil
+}
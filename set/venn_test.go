@@ -0,0 +1,85 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_Selection(t *testing.T) {
+	s := Selection(0b1011)
+	if s.Len() != 3 || !s.Contains(0) || s.Contains(2) || !s.Contains(3) || s.Contains(64) {
+		t.Errorf("Expecting members 0, 1 & 3 in %b", s)
+	}
+	if idx := s.Indices(); len(idx) != 3 || idx[0] != 0 || idx[1] != 1 || idx[2] != 3 {
+		t.Errorf("Expecting indices [0 1 3] instead got %v", idx)
+	}
+}
+
+func Test_VennRegions(t *testing.T) {
+	F := Family{NewSet(1, 2, 3, 4), NewSet(2, 3, 5), NewSet(3, 6)}
+	want := map[Selection]*Set{
+		0b001: NewSet(1, 4),
+		0b010: NewSet(5),
+		0b011: NewSet(2),
+		0b100: NewSet(6),
+		0b101: NewSet(),
+		0b110: NewSet(),
+		0b111: NewSet(3),
+	}
+	regions, err := F.VennRegions()
+	if err != nil || len(regions) != 7 {
+		t.Fatalf("Expecting 7 regions instead got %d, %v", len(regions), err)
+	}
+	for i, r := range regions {
+		if r.In != Selection(i+1) || !r.Set.IsEqual(want[r.In]) {
+			t.Errorf("Expecting region %b to be %v instead got %b: %v", i+1, want[Selection(i+1)], r.In, r.Set)
+		}
+	}
+	sizes, _ := F.IntersectionSizes()
+	wantSizes := map[Selection]int{0b001: 4, 0b010: 3, 0b100: 2, 0b011: 2, 0b101: 1, 0b110: 1, 0b111: 1}
+	if len(sizes) != len(wantSizes) {
+		t.Errorf("Expecting %v instead got %v", wantSizes, sizes)
+	}
+	for s, n := range wantSizes {
+		if sizes[s] != n {
+			t.Errorf("Expecting |⋂%v| = %d instead got %d", s.Indices(), n, sizes[s])
+		}
+	}
+	if n := InclusionExclusion(sizes); n != 6 {
+		t.Errorf("Expecting a union of 6 instead got %d", n)
+	}
+	if _, err := make(Family, MaxVennSets+1).VennRegions(); err != ErrTooManySets {
+		t.Errorf("Expecting ErrTooManySets instead got %v", err)
+	}
+	if regions, _ := (Family{}).VennRegions(); len(regions) != 0 {
+		t.Errorf("Expecting no regions of the empty family instead got %v", regions)
+	}
+}
+
+func Test_CheckInclusionExclusion(t *testing.T) {
+	r := rand.New(rand.NewSource(50))
+	for trial := 0; trial < 30; trial++ {
+		F := make(Family, r.Intn(8))
+		for i := range F {
+			F[i] = NewSet()
+			for j := r.Intn(25); j > 0; j-- {
+				F[i].Add(r.Intn(30))
+			}
+		}
+		n, err := F.CheckInclusionExclusion()
+		if err != nil || n != F.BigUnion().Len() {
+			t.Errorf("Expecting a union of %d instead got %d, %v", F.BigUnion().Len(), n, err)
+		}
+		// The regions of a given number of members hold the elements in exactly that many.
+		regions, _ := F.VennRegions()
+		counts := make([]int, len(F)+1)
+		for _, r := range regions {
+			counts[r.In.Len()] += r.Set.Len()
+		}
+		for k := 1; k <= len(F); k++ {
+			if counts[k] != F.CountExactly(k) {
+				t.Errorf("Expecting %d elements in exactly %d members instead got %d", F.CountExactly(k), k, counts[k])
+			}
+		}
+	}
+}
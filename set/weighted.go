@@ -0,0 +1,194 @@
+package set
+
+import (
+	"math"
+	"sort"
+)
+
+// Weights maps elements to weights, such as the scores of a recommendation vector. Weights must
+// be finite; an element with a weight of 0 or less is treated like one that is not in the map.
+type Weights map[interface{}]float64
+
+// WeightsFromSet returns weights of 1 for every element of A. The weighted similarities of such
+// weights are equal to the similarities of the sets.
+func WeightsFromSet(A Interface) Weights {
+	w := make(Weights, A.Len())
+	A.Iterate(func(e interface{}) bool {
+		w[e] = 1
+		return true
+	})
+	return w
+}
+
+// weightedSums returns Σmin(x,y), Σmax(x,y), Σx and Σy over the elements with a positive weight.
+func weightedSums(x, y Weights) (min, max, sx, sy float64) {
+	for e, wx := range x {
+		if wx <= 0 {
+			continue
+		}
+		wy := math.Max(y[e], 0)
+		min += math.Min(wx, wy)
+		max += math.Max(wx, wy)
+		sx += wx
+	}
+	for e, wy := range y {
+		if wy <= 0 {
+			continue
+		}
+		if x[e] <= 0 {
+			max += wy
+		}
+		sy += wy
+	}
+	return
+}
+
+// WeightedJaccardSimilarity
+// The weighted (Ruzicka) Jaccard index generalises JaccardSimilarity to weighted elements.
+//
+// J(x,y) = Σ min(xᵢ,yᵢ) / Σ max(xᵢ,yᵢ)
+//
+// It is undefined, NaN unless opts say otherwise, when both weightings are empty.
+func WeightedJaccardSimilarity(x, y Weights, opts ...SimilarityOption) float64 {
+	min, max, _, _ := weightedSums(x, y)
+	if max == 0 {
+		return undefined("WeightedJaccardSimilarity", math.NaN(), opts)
+	}
+	return min / max
+}
+
+// WeightedDSC
+// The weighted Dice coefficient generalises DSC to weighted elements.
+//
+// DSC(x,y) = 2 Σ min(xᵢ,yᵢ) / (Σ xᵢ + Σ yᵢ)
+//
+// It is undefined, NaN unless opts say otherwise, when both weightings are empty.
+func WeightedDSC(x, y Weights, opts ...SimilarityOption) float64 {
+	min, _, sx, sy := weightedSums(x, y)
+	if sx+sy == 0 {
+		return undefined("WeightedDSC", math.NaN(), opts)
+	}
+	return 2 * min / (sx + sy)
+}
+
+// WeightedOverlapCoefficient
+// The weighted overlap coefficient generalises OverlapCoefficient to weighted elements.
+//
+// O(x,y) = Σ min(xᵢ,yᵢ) / min(Σ xᵢ, Σ yᵢ)
+//
+// It is undefined, NaN unless opts say otherwise, when either weighting is empty.
+func WeightedOverlapCoefficient(x, y Weights, opts ...SimilarityOption) float64 {
+	min, _, sx, sy := weightedSums(x, y)
+	if sx == 0 || sy == 0 {
+		return undefined("WeightedOverlapCoefficient", math.NaN(), opts)
+	}
+	return min / math.Min(sx, sy)
+}
+
+// ProbabilityJaccardSimilarity
+// The probability Jaccard index treats the weights as unnormalised probability distributions
+// and is invariant to scaling either of them. It is the highest collision probability any
+// sampling scheme can achieve between the two distributions, which makes it the natural target
+// for sampling methods. When every weight is 1 it is equal to JaccardSimilarity.
+//
+// Jp(x,y) = Σ_{xᵢ,yᵢ>0} 1 / Σⱼ max(xⱼ/xᵢ, yⱼ/yᵢ)
+//
+// It is undefined, NaN unless opts say otherwise, when both weightings are empty.
+func ProbabilityJaccardSimilarity(x, y Weights, opts ...SimilarityOption) float64 {
+	type pair struct{ x, y, ratio float64 }
+	pairs := make([]pair, 0, len(x)+len(y))
+	for e, wx := range x {
+		if wx > 0 {
+			wy := math.Max(y[e], 0)
+			pairs = append(pairs, pair{wx, wy, wx / wy})
+		}
+	}
+	for e, wy := range y {
+		if wy > 0 && x[e] <= 0 {
+			pairs = append(pairs, pair{0, wy, 0})
+		}
+	}
+	if len(pairs) == 0 {
+		return undefined("ProbabilityJaccardSimilarity", math.NaN(), opts)
+	}
+	// max(xⱼ/xᵢ, yⱼ/yᵢ) is xⱼ/xᵢ exactly when xⱼ/yⱼ ≥ xᵢ/yᵢ, so with the elements sorted by that
+	// ratio each inner sum splits into a suffix sum of x and a prefix sum of y.
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].ratio < pairs[j].ratio })
+	suffixX := make([]float64, len(pairs)+1)
+	for i := len(pairs) - 1; i >= 0; i-- {
+		suffixX[i] = suffixX[i+1] + pairs[i].x
+	}
+	var prefixY, j float64
+	for i, p := range pairs {
+		if p.x > 0 && p.y > 0 {
+			j += 1 / (suffixX[i]/p.x + prefixY/p.y)
+		}
+		prefixY += p.y
+	}
+	return j
+}
+
+// WeightedJaccardSimilarityErr is WeightedJaccardSimilarity with an undefined index reported as an error.
+func WeightedJaccardSimilarityErr(x, y Weights, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return WeightedJaccardSimilarity(x, y, o...) })
+}
+
+// WeightedDSCErr is WeightedDSC with an undefined coefficient reported as an error.
+func WeightedDSCErr(x, y Weights, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return WeightedDSC(x, y, o...) })
+}
+
+// WeightedOverlapCoefficientErr is WeightedOverlapCoefficient with an undefined coefficient reported as an error.
+func WeightedOverlapCoefficientErr(x, y Weights, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return WeightedOverlapCoefficient(x, y, o...) })
+}
+
+// ProbabilityJaccardSimilarityErr is ProbabilityJaccardSimilarity with an undefined index reported as an error.
+func ProbabilityJaccardSimilarityErr(x, y Weights, opts ...SimilarityOption) (float64, error) {
+	return checked(opts, func(o ...SimilarityOption) float64 { return ProbabilityJaccardSimilarity(x, y, o...) })
+}
+
+// WeightedSignature returns the consistent weighted sampling (ICWS) signature of w. Each value
+// samples an element with probability proportional to its weight, in such a way that two
+// signatures agree at any position with probability equal to the WeightedJaccardSimilarity of
+// their weights, so Signature.Jaccard of two weighted signatures estimates it with the same
+// standard error as MinHash. Weighted signatures can only be compared with each other.
+//
+// For each position and element, with r, c ~ Γ(2,1) and β ~ U(0,1) drawn from hashes,
+//
+// t = ⌊ln w / r + β⌋, ln a = ln c − r(t − β + 1), sample = argmin a and its t
+func (h *MinHasher) WeightedSignature(w Weights) Signature {
+	s := Signature{values: make([]uint64, h.k), seed: h.seed, weighted: true}
+	best := make([]float64, h.k)
+	for i := range s.values {
+		s.values[i] = emptyBin
+		best[i] = math.Inf(1)
+	}
+	for e, weight := range w {
+		if weight <= 0 {
+			continue
+		}
+		x := hashElement(e, h.seed)
+		lw := math.Log(weight)
+		for i, seed := range h.seeds {
+			u := uniforms(mix64(x ^ seed))
+			r := -math.Log(u[0] * u[1])
+			c := -math.Log(u[2] * u[3])
+			t := math.Floor(lw/r + u[4])
+			if la := math.Log(c) - r*(t-u[4]+1); la < best[i] {
+				best[i] = la
+				s.values[i] = mix64(x ^ mix64(uint64(int64(t))^seed))
+			}
+		}
+	}
+	return s
+}
+
+// uniforms returns five numbers in (0,1) derived from x.
+func uniforms(x uint64) (u [5]float64) {
+	for i := range u {
+		x = mix64(x + 0x9e3779b97f4a7c15)
+		u[i] = (float64(x>>11) + 0.5) / (1 << 53)
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package set
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_WeightedSimilarity(t *testing.T) {
+	x := Weights{"a": 1, "b": 2, "c": 3}
+	y := Weights{"b": 1, "c": 3, "d": 2, "e": 0}
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Jaccard", WeightedJaccardSimilarity(x, y), 0.5},
+		{"Dice", WeightedDSC(x, y), 2.0 / 3},
+		{"Overlap", WeightedOverlapCoefficient(x, y), 2.0 / 3},
+		{"Probability Jaccard", ProbabilityJaccardSimilarity(x, y), 55.0 / 104},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+	scaled := Weights{"a": 10, "b": 20, "c": 30}
+	if p := ProbabilityJaccardSimilarity(scaled, y); math.Abs(p-55.0/104) > 1e-12 {
+		t.Errorf("Expecting probability Jaccard to ignore scale instead got %f", p)
+	}
+}
+
+func Test_WeightedSimilarityUnitWeights(t *testing.T) {
+	A := NewSet(0, 1, 2, 5, 6, 8, 9)
+	B := NewSet(0, 2, 3, 4, 5, 7, 9)
+	x, y := WeightsFromSet(A), WeightsFromSet(B)
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Jaccard", WeightedJaccardSimilarity(x, y), JaccardSimilarity(A, B)},
+		{"Probability Jaccard", ProbabilityJaccardSimilarity(x, y), JaccardSimilarity(A, B)},
+		{"Dice", WeightedDSC(x, y), DSC(A, B)},
+		{"Overlap", WeightedOverlapCoefficient(x, y), OverlapCoefficient(A, B)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s: expecting %f instead got %f", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func Test_WeightedSignature(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	x, y := Weights{}, Weights{}
+	for i := 0; i < 300; i++ {
+		if i < 200 {
+			x[i] = r.Float64() * 10
+		}
+		if i >= 100 {
+			y[i] = r.Float64() * 10
+		}
+	}
+	h, _ := NewMinHasher(1024, 3)
+	for _, tt := range []struct{ x, y Weights }{{x, y}, {x, x}, {WeightsFromSet(intSet(0, 100)), WeightsFromSet(intSet(50, 150))}} {
+		want := WeightedJaccardSimilarity(tt.x, tt.y)
+		got, err := h.WeightedSignature(tt.x).Jaccard(h.WeightedSignature(tt.y))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-want) > 4*h.StandardError(want)+1e-9 {
+			t.Errorf("Expecting an estimate near %f instead got %f", want, got)
+		}
+	}
+	if _, err := h.WeightedSignature(x).Jaccard(h.Signature(intSet(0, 10))); err != ErrIncompatibleSketches {
+		t.Errorf("Expecting ErrIncompatibleSketches instead got %v", err)
+	}
+	if j, _ := h.WeightedSignature(Weights{}).Jaccard(h.WeightedSignature(x)); j != 0 {
+		t.Errorf("Expecting 0 against an empty weighting instead got %f", j)
+	}
+}
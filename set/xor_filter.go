@@ -0,0 +1,160 @@
+package set
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// ErrFilterConstruction is returned when a static filter could not be built from its elements.
+var ErrFilterConstruction = errors.New("set: filter construction failed")
+
+// xorMaxAttempts is the number of seeds tried before giving up building an XorFilter. An attempt
+// fails when peeling stops at a cycle in the 3-hypergraph of the slots of the keys, which at
+// 1.23 slots per key happens for about 1–2% of seeds, so 100 failures in a row should never
+// be seen. A repeated key would make a cycle under every seed, which is why the key hashes are
+// deduplicated before construction.
+const xorMaxAttempts = 100
+
+// XorFilter is a static probabilistic set built once from a Set. It stores an 8-bit fingerprint
+// in about 1.23 slots per element, roughly 9.84 bits per element, for a false-positive rate of
+// 2⁻⁸ ≈ 0.39%. That is smaller than a BloomFilter of the same rate, but no elements can be
+// added or removed after construction.
+//
+// An element x is in the filter when fp(x) = F[h₀(x)] ⊕ F[h₁(x)] ⊕ F[h₂(x)].
+type XorFilter struct {
+	seed         uint64
+	blockLength  uint32
+	fingerprints []uint8
+}
+
+// NewXorFilter returns the XOR filter of the elements of A.
+// ErrFilterConstruction is returned if no suitable hash seed was found.
+func NewXorFilter(A Interface) (*XorFilter, error) {
+	keys := make([]uint64, 0, A.Len())
+	A.Iterate(func(e interface{}) bool {
+		keys = append(keys, hashElement(e, 0))
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	unique := keys[:0]
+	for i, k := range keys {
+		if i == 0 || k != keys[i-1] {
+			unique = append(unique, k)
+		}
+	}
+	return buildXorFilter(unique)
+}
+
+func buildXorFilter(keys []uint64) (*XorFilter, error) {
+	size := 32 + uint32(1.23*float64(len(keys)))
+	blockLength := size / 3
+	f := &XorFilter{
+		blockLength:  blockLength,
+		fingerprints: make([]uint8, 3*blockLength),
+	}
+	type slot struct {
+		mask  uint64
+		count uint32
+	}
+	type peeled struct {
+		hash  uint64
+		index uint32
+	}
+	slots := make([]slot, len(f.fingerprints))
+	stack := make([]peeled, 0, len(keys))
+	queue := make([]uint32, 0, len(f.fingerprints))
+	rng := uint64(0x726f7567686e6573)
+	for attempt := 0; attempt < xorMaxAttempts; attempt++ {
+		rng += 0x9e3779b97f4a7c15
+		f.seed = mix64(rng)
+		for i := range slots {
+			slots[i] = slot{}
+		}
+		for _, k := range keys {
+			h := f.hash(k)
+			for _, i := range f.positions(h) {
+				slots[i].mask ^= h
+				slots[i].count++
+			}
+		}
+		queue = queue[:0]
+		for i := range slots {
+			if slots[i].count == 1 {
+				queue = append(queue, uint32(i))
+			}
+		}
+		stack = stack[:0]
+		for len(queue) > 0 {
+			i := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			if slots[i].count != 1 {
+				continue
+			}
+			h := slots[i].mask
+			stack = append(stack, peeled{h, i})
+			for _, j := range f.positions(h) {
+				slots[j].mask ^= h
+				slots[j].count--
+				if slots[j].count == 1 {
+					queue = append(queue, j)
+				}
+			}
+		}
+		if len(stack) == len(keys) {
+			for n := len(stack) - 1; n >= 0; n-- {
+				p := stack[n]
+				fp := xorFingerprint(p.hash)
+				for _, j := range f.positions(p.hash) {
+					if j != p.index {
+						fp ^= f.fingerprints[j]
+					}
+				}
+				f.fingerprints[p.index] = fp
+			}
+			return f, nil
+		}
+	}
+	return nil, ErrFilterConstruction
+}
+
+func (f *XorFilter) hash(key uint64) uint64 {
+	return mix64(key + f.seed)
+}
+
+// positions returns the slot of h in each of the three blocks of f.
+func (f *XorFilter) positions(h uint64) [3]uint32 {
+	return [3]uint32{
+		reduce(uint32(h), f.blockLength),
+		reduce(uint32(bits.RotateLeft64(h, 21)), f.blockLength) + f.blockLength,
+		reduce(uint32(bits.RotateLeft64(h, 42)), f.blockLength) + 2*f.blockLength,
+	}
+}
+
+// reduce maps x uniformly onto [0,n) without a division.
+func reduce(x, n uint32) uint32 {
+	return uint32((uint64(x) * uint64(n)) >> 32)
+}
+
+func xorFingerprint(h uint64) uint8 {
+	return uint8(h ^ (h >> 32))
+}
+
+// MayContain reports whether e may be in f. False means e is definitely not in f.
+func (f *XorFilter) MayContain(e interface{}) bool {
+	h := f.hash(hashElement(e, 0))
+	p := f.positions(h)
+	return xorFingerprint(h) == f.fingerprints[p[0]]^f.fingerprints[p[1]]^f.fingerprints[p[2]]
+}
+
+// FalsePositiveRate returns the probability that MayContain reports an element that is not in f.
+//
+// p = 2⁻⁸
+func (f *XorFilter) FalsePositiveRate() float64 {
+	return 1.0 / 256
+}
+
+// Bits returns the size of f in bits.
+func (f *XorFilter) Bits() uint64 {
+	return uint64(len(f.fingerprints)) * 8
+}
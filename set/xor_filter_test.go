@@ -0,0 +1,39 @@
+package set
+
+import "testing"
+
+func Test_XorFilter(t *testing.T) {
+	A := NewSet()
+	for i := 0; i < 10000; i++ {
+		A.Add(i)
+	}
+	f, err := NewXorFilter(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for e := range A.E {
+		if !f.MayContain(e) {
+			t.Fatalf("Expecting no false negatives, %v is missing", e)
+		}
+	}
+	fp := 0
+	trials := 200000
+	for i := 0; i < trials; i++ {
+		if f.MayContain(-1 - i) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / float64(trials); rate > 1.25*f.FalsePositiveRate() {
+		t.Errorf("Expecting a false-positive rate of about %v instead measured %v", f.FalsePositiveRate(), rate)
+	}
+	if bpe := float64(f.Bits()) / 10000; bpe > 10 {
+		t.Errorf("Expecting under 10 bits per element instead got %f", bpe)
+	}
+	b, _ := NewBloomFilter(10000, f.FalsePositiveRate())
+	if f.Bits() >= b.Bits() {
+		t.Errorf("Expecting the XOR filter (%d bits) to be smaller than a Bloom filter (%d bits)", f.Bits(), b.Bits())
+	}
+	if _, err := NewXorFilter(NewSet()); err != nil {
+		t.Errorf("Expecting an empty filter to build, got %v", err)
+	}
+}